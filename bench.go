@@ -0,0 +1,72 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"testing"
+)
+
+// B wraps a testing.B and implements LikeC. It is passed to benchmark
+// methods, and to fixture methods taking LikeC, when a suite is run with
+// RunB.
+type B struct {
+	*testing.B
+	likeC
+}
+
+var _ LikeC = (*B)(nil)
+
+func newB(b *testing.B) *B {
+	return &B{B: b, likeC: likeC{&TBC{TB: b}}}
+}
+
+// RunB runs the methods starting with the Benchmark prefix in the provided
+// suite as sub-benchmarks of b. SetUpSuite and TearDownSuite are run once
+// around all of them, and SetUpTest and TearDownTest around each run of
+// a benchmark method, outside of the benchmark timer.
+//
+// Fixture methods are passed a *B rather than a *C, so fixtures shared with
+// tests run by Run must take a LikeC.
+func RunB(b *testing.B, suite any) {
+	b.Helper()
+	runner := newSuiteRunner(suite)
+	runner.runBenchmarks(b)
+}
+
+// Run all benchmark methods in the given suite.
+func (runner *suiteRunner) runBenchmarks(b *testing.B) {
+	c := newB(b)
+	runner.validateSuite(c, entryRunB)
+
+	runBetween(c, func(called *int) {
+		runner.setUpSuite.setUp(c, called)
+	}, func(called int) {
+		runner.tearDownSuite.tearDown(c, called)
+	}, nil)
+
+	for _, bench := range runner.benchmarks {
+		b.Run(bench.Info.Name, func(b *testing.B) {
+			runner.runBenchmark(b, bench)
+		})
+	}
+}
+
+// runBenchmark runs a single benchmark method, which the testing package
+// may do several times with an increasing b.N.
+func (runner *suiteRunner) runBenchmark(b *testing.B, method *methodType) {
+	c := newB(b)
+	// The testing package runs cleanups after every run of the benchmark
+	// function, so the teardown happens once per run.
+	runBetween(c, func(called *int) {
+		b.StopTimer()
+		runner.setUpTest.setUp(c, called)
+	}, func(called int) {
+		b.StopTimer()
+		runner.tearDownTest.tearDown(c, called)
+	}, func() {
+		b.ResetTimer()
+		b.StartTimer()
+		method.Call(c)
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc_test
+
+import (
+	"testing"
+
+	"github.com/juju/tc"
+)
+
+type BenchS struct{}
+
+var _ = tc.InternalSuite(&BenchS{})
+
+type benchHelper struct {
+	calls []string
+}
+
+func (s *benchHelper) SetUpSuite(c tc.LikeC) {
+	s.calls = append(s.calls, "SetUpSuite")
+}
+
+func (s *benchHelper) TearDownSuite(c tc.LikeC) {
+	s.calls = append(s.calls, "TearDownSuite")
+}
+
+func (s *benchHelper) SetUpTest(c tc.LikeC) {
+	s.calls = append(s.calls, "SetUpTest")
+}
+
+func (s *benchHelper) TearDownTest(c tc.LikeC) {
+	s.calls = append(s.calls, "TearDownTest")
+}
+
+func (s *benchHelper) BenchmarkLoop(b *tc.B) {
+	s.calls = append(s.calls, "BenchmarkLoop")
+	for b.Loop() {
+	}
+	b.Check(b.MkDir(), tc.Not(tc.Equals), "")
+}
+
+func (s *benchHelper) TestNotABenchmark(c *tc.C) {
+	s.calls = append(s.calls, "TestNotABenchmark")
+}
+
+func (s *BenchS) TestRunB(c *tc.C) {
+	suite := &benchHelper{}
+	result := testing.Benchmark(func(b *testing.B) {
+		tc.RunB(b, suite)
+	})
+	c.Check(result.N, tc.Not(tc.Equals), 0)
+
+	c.Assert(len(suite.calls) >= 5, tc.IsTrue)
+	c.Check(suite.calls[0], tc.Equals, "SetUpSuite")
+	c.Check(suite.calls[len(suite.calls)-1], tc.Equals, "TearDownSuite")
+	runs := suite.calls[1 : len(suite.calls)-1]
+	c.Assert(len(runs)%3, tc.Equals, 0)
+	for i := 0; i < len(runs); i += 3 {
+		c.Check(runs[i:i+3], tc.DeepEquals, []string{
+			"SetUpTest", "BenchmarkLoop", "TearDownTest",
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// casesPrefix prefixes the name of a table driven test method to give the
+// name of the suite method providing its cases. Table driven test methods
+// take a case after the *C, as in
+//
+//	func (s *S) CasesForTestParse() []parseCase { ... }
+//	func (s *S) TestParse(c *tc.C, tc parseCase) { ... }
+//
+// The provider returns either a slice of cases, each run as a subtest named
+// after its Name field if it has one or otherwise its index, or a map of
+// cases keyed by subtest name. Each case is run with its own SetUpTest and
+// TearDownTest. Providers are called after SetUpSuite.
+const casesPrefix = "CasesFor"
+
+// testCase is a single case of a table driven test method.
+type testCase struct {
+	name  string
+	value reflect.Value
+}
+
+// isTableTest reports whether the test method takes a case.
+func isTableTest(method *methodType) bool {
+	return method.Info.Type.NumIn() == firstArg(method.Info.Type)+2
+}
+
+// casesProvider returns the method of the suite type providing the cases
+// for the given table driven test method, checking that their types match.
+func casesProvider(suiteType reflect.Type, method reflect.Method) (reflect.Method, error) {
+	name := casesPrefix + method.Name
+	provider, ok := suiteType.MethodByName(name)
+	if !ok {
+		return provider, fmt.Errorf("test method %s takes a case but the suite has no %s method", method.Name, name)
+	}
+	caseType := method.Type.In(firstArg(method.Type) + 1)
+	providerType := provider.Type
+	if providerType.NumIn() != 1 || providerType.NumOut() != 1 {
+		return provider, fmt.Errorf("method %s must take no arguments and return the cases for %s", name, method.Name)
+	}
+	out := providerType.Out(0)
+	switch {
+	case out.Kind() == reflect.Slice && out.Elem().AssignableTo(caseType):
+	case out.Kind() == reflect.Map && out.Key().Kind() == reflect.String && out.Elem().AssignableTo(caseType):
+	default:
+		return provider, fmt.Errorf("method %s must return a slice of %s, or a map of them keyed by string, not %s",
+			name, caseType, out)
+	}
+	return provider, nil
+}
+
+// testCases returns the cases for the given table driven test method.
+func (runner *suiteRunner) testCases(method *methodType) ([]testCase, error) {
+	suiteValue := reflect.ValueOf(runner.suite)
+	provider, err := casesProvider(suiteValue.Type(), method.Info)
+	if err != nil {
+		return nil, err
+	}
+	cases := suiteValue.Method(provider.Index).Call(nil)[0]
+
+	var result []testCase
+	switch cases.Kind() {
+	case reflect.Slice:
+		for i := 0; i < cases.Len(); i++ {
+			result = append(result, testCase{
+				name:  caseName(cases.Index(i), i),
+				value: cases.Index(i),
+			})
+		}
+	case reflect.Map:
+		for _, key := range cases.MapKeys() {
+			result = append(result, testCase{
+				name:  key.String(),
+				value: cases.MapIndex(key),
+			})
+		}
+		sort.Slice(result, func(i, j int) bool {
+			return result[i].name < result[j].name
+		})
+	}
+	return result, nil
+}
+
+// caseName returns the subtest name for the case at index i of a slice of
+// cases: the case's Name or name string field, if it has one that is set,
+// or else the index.
+func caseName(value reflect.Value, i int) string {
+	value = reflect.Indirect(value)
+	if value.Kind() == reflect.Struct {
+		for _, field := range []string{"Name", "name"} {
+			name := value.FieldByName(field)
+			if name.Kind() == reflect.String && name.String() != "" {
+				return name.String()
+			}
+		}
+	}
+	return strconv.Itoa(i)
+}
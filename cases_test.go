@@ -0,0 +1,80 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc_test
+
+import (
+	"testing"
+
+	"github.com/juju/tc"
+)
+
+type CasesS struct{}
+
+var _ = tc.InternalSuite(&CasesS{})
+
+type doubleCase struct {
+	Name    string
+	in, out int
+}
+
+type casesHelper struct {
+	calls []string
+}
+
+func (s *casesHelper) SetUpTest(c *tc.C) {
+	s.calls = append(s.calls, "SetUpTest")
+}
+
+func (s *casesHelper) TearDownTest(c *tc.C) {
+	s.calls = append(s.calls, "TearDownTest")
+}
+
+func (s *casesHelper) CasesForTestDouble() []doubleCase {
+	return []doubleCase{
+		{Name: "one", in: 1, out: 2},
+		{in: 2, out: 4},
+	}
+}
+
+func (s *casesHelper) TestDouble(c *tc.C, tcase doubleCase) {
+	s.calls = append(s.calls, c.TestName())
+	c.Check(tcase.in*2, tc.Equals, tcase.out)
+}
+
+func (s *casesHelper) CasesForTestKeyed() map[string]int {
+	return map[string]int{"b": 2, "a": 1}
+}
+
+func (s *casesHelper) TestKeyed(c *tc.C, n int) {
+	s.calls = append(s.calls, c.TestName())
+	c.Check(n > 0, tc.IsTrue)
+}
+
+func (s *CasesS) TestCases(c *tc.C) {
+	suite := &casesHelper{}
+	c.T.Run("casesHelper", func(t *testing.T) {
+		tc.Run(t, suite)
+	})
+	prefix := c.TestName() + "/casesHelper/"
+	c.Check(suite.calls, tc.DeepEquals, []string{
+		"SetUpTest", prefix + "TestDouble/one", "TearDownTest",
+		"SetUpTest", prefix + "TestDouble/1", "TearDownTest",
+		"SetUpTest", prefix + "TestKeyed/a", "TearDownTest",
+		"SetUpTest", prefix + "TestKeyed/b", "TearDownTest",
+	})
+}
+
+type badCasesHelper struct{}
+
+func (s *badCasesHelper) CasesForTestWrongType() []string {
+	return nil
+}
+
+func (s *badCasesHelper) TestWrongType(c *tc.C, n int) {}
+
+func (s *CasesS) TestBadCases(c *tc.C) {
+	problems := tc.Validate(&badCasesHelper{})
+	c.Assert(problems, tc.HasLen, 1)
+	c.Check(problems[0], tc.Matches, `.*/cases_test.go:\d+: method CasesForTestWrongType must return a slice of int, or a map of them keyed by string, not \[\]string`)
+}
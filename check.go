@@ -33,11 +33,15 @@
 package tc
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"math/rand"
 	"reflect"
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -55,6 +59,14 @@ func newMethod(receiver reflect.Value, i int) *methodType {
 	return &methodType{receiver.Method(i), receiver.Type().Method(i)}
 }
 
+// bind returns the same method bound to the given receiver.
+func (method *methodType) bind(receiver reflect.Value) *methodType {
+	if method == nil {
+		return nil
+	}
+	return &methodType{receiver.Method(method.Info.Index), method.Info}
+}
+
 func (method *methodType) PC() uintptr {
 	return method.Info.Func.Pointer()
 }
@@ -71,26 +83,182 @@ func (method *methodType) String() string {
 	return method.suiteName() + "." + method.Info.Name
 }
 
-func (m *methodType) Call(c *C) {
+func (m *methodType) Call(c LikeC, args ...reflect.Value) {
 	if m == nil {
 		return
 	}
 
-	c.method = m
-	defer func() {
-		c.method = nil
-	}()
+	if c, ok := c.(*C); ok {
+		c.method = m
+		defer func() {
+			c.method = nil
+		}()
+	}
 
 	c.Helper()
 
-	switch {
-	case m.Info.Type.In(1) == reflect.TypeOf((*C)(nil)) && m.Info.Type.NumIn() == 2:
-		m.Value.Call([]reflect.Value{reflect.ValueOf(c)})
-	case m.Info.Type.In(1) == reflect.TypeOf((*testing.T)(nil)) && m.Info.Type.NumIn() == 2:
-		m.Value.Call([]reflect.Value{reflect.ValueOf(c.T)})
-	default:
+	in, ok := methodArgs(m.Info.Type, len(args), c)
+	if !ok {
 		c.Fatalf("bad signature for method %s: %T", m.Info.Name, m.Interface())
 	}
+	out := m.Value.Call(append(in, args...))
+	if len(out) == 1 && !out[0].IsNil() {
+		m.failWithError(c, out[0].Interface().(error))
+		// The rest of a teardown chain still needs to run.
+		if !strings.HasPrefix(m.Info.Name, "TearDown") {
+			c.FailNow()
+		}
+	}
+}
+
+// failWithError fails c because the method returned err, formatting the
+// error as a failed ErrorIsNil check would.
+func (m *methodType) failWithError(c LikeC, err error) {
+	c.Helper()
+	lines := []string{}
+	frame, _ := runtime.CallersFrames([]uintptr{m.PC()}).Next()
+	if frame.File != "" {
+		lines = append(lines, fmt.Sprintf("%s:%d", frame.File, frame.Line))
+	}
+	lines = append(lines,
+		fmt.Sprintf("... %s returned an error", m.Info.Name),
+		formatValue("value", err))
+	if _, message := ErrorIsNil.Check([]any{err}, []string{"value"}); message != "" {
+		lines = append(lines, "... "+message)
+	}
+	message := strings.Join(lines, "\n")
+	if c, ok := c.(*C); ok {
+		c.fail(message)
+		return
+	}
+	c.Error("\n" + message)
+}
+
+var (
+	cType      = reflect.TypeOf((*C)(nil))
+	bType      = reflect.TypeOf((*B)(nil))
+	fType      = reflect.TypeOf((*F)(nil))
+	testingT   = reflect.TypeOf((*testing.T)(nil))
+	testingB   = reflect.TypeOf((*testing.B)(nil))
+	testingTB  = reflect.TypeOf((*testing.TB)(nil)).Elem()
+	likeTBType = reflect.TypeOf((*LikeTB)(nil)).Elem()
+	likeCType  = reflect.TypeOf((*LikeC)(nil)).Elem()
+
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// firstArg returns the index, counting the receiver, of the parameter of
+// a suite method of the given type that takes the test, which comes after
+// a context.Context if the method takes one.
+func firstArg(methodType reflect.Type) int {
+	if methodType.NumIn() > 1 && methodType.In(1) == contextType {
+		return 2
+	}
+	return 1
+}
+
+// methodArgs returns the arguments to pass to a suite method of the given
+// type when calling it with c, ahead of the given number of extra
+// arguments. It returns false if the method doesn't take something c can
+// provide, optionally after a context.Context, or if it returns anything
+// but a single error.
+func methodArgs(methodType reflect.Type, extra int, c LikeC) ([]reflect.Value, bool) {
+	first := firstArg(methodType)
+	if methodType.NumIn() != first+1+extra || !validResults(methodType) {
+		return nil, false
+	}
+	arg, ok := methodArg(methodType.In(first), c)
+	if !ok {
+		return nil, false
+	}
+	if first == 1 {
+		return []reflect.Value{arg}, true
+	}
+	return []reflect.Value{reflect.ValueOf(testContext(c)), arg}, true
+}
+
+// validResults reports whether a suite method of the given type returns
+// nothing, or only an error.
+func validResults(methodType reflect.Type) bool {
+	return methodType.NumOut() == 0 || methodType.NumOut() == 1 && methodType.Out(0) == errorType
+}
+
+// testContext returns the context passed to suite methods taking one. For
+// a test body that is one cancelled as soon as the body returns, before
+// TearDownTest is called.
+func testContext(c LikeC) context.Context {
+	if c, ok := c.(*C); ok && c.ctx != nil {
+		return c.ctx
+	}
+	return c.Context()
+}
+
+// methodArg returns the argument to pass to a suite method taking the
+// given type for the test. It returns false if c can't provide it.
+func methodArg(in reflect.Type, c LikeC) (reflect.Value, bool) {
+	switch in {
+	case likeCType, likeTBType, testingTB:
+		return reflect.ValueOf(c), true
+	case cType, bType, fType:
+		if reflect.TypeOf(c) == in {
+			return reflect.ValueOf(c), true
+		}
+	case testingT:
+		if c, ok := c.(*C); ok {
+			return reflect.ValueOf(c.T), true
+		}
+	case testingB:
+		if c, ok := c.(*B); ok {
+			return reflect.ValueOf(c.B), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// chain holds a fixture method for each layer of a suite, innermost layer
+// first. Without fixture chaining the suite has a single layer, and a nil
+// entry stands for a layer that lacks the fixture method.
+type chain []*methodType
+
+// setUp calls the method of each layer in turn, keeping count in called of
+// the layers reached so far, so that only those are torn down.
+func (ch chain) setUp(c LikeC, called *int) {
+	c.Helper()
+	for _, method := range ch {
+		*called++
+		ch.call(c, method)
+	}
+}
+
+// tearDown calls the methods of the first called layers in reverse order.
+// Every one of them is called, even if an earlier one stops the test.
+func (ch chain) tearDown(c LikeC, called int) {
+	c.Helper()
+	for _, method := range ch[:called] {
+		defer ch.call(c, method)
+	}
+}
+
+func (ch chain) call(c LikeC, method *methodType) {
+	c.Helper()
+	if len(ch) > 1 && method != nil {
+		failed := c.Failed()
+		defer func() {
+			if !failed && c.Failed() {
+				fmt.Fprintf(c.Output(), "fixture %s failed\n", method)
+			}
+		}()
+	}
+	method.Call(c)
+}
+
+func (ch chain) bind(receiver reflect.Value) chain {
+	bound := make(chain, len(ch))
+	for i, method := range ch {
+		bound[i] = method.bind(receiver)
+	}
+	return bound
 }
 
 type C struct {
@@ -99,17 +267,85 @@ type C struct {
 	method    *methodType
 	reason    string
 	startTime time.Time
+	// runner runs the suite the test belongs to, and its subtests'
+	// fixtures.
+	runner *suiteRunner
+	// fixture is the value made for the test by RunSuite's setup.
+	fixture any
+	// ctx is the context passed to the test body, while it runs.
+	ctx context.Context
+
+	// mu guards reason and the fields below, which may be recorded from
+	// any goroutine.
+	mu       sync.Mutex
+	failures []string
+	timings  map[string]time.Duration
+	// attempt holds the outcome of the current attempt of a retried or
+	// quarantined test, in place of its *testing.T.
+	attempt *attempt
+	// attempts is how many times a retried test was run.
+	attempts int
+	// status overrides the status reported for a flaky or quarantined
+	// test.
+	status string
+	// rand is the test's source of random numbers, once Rand is called.
+	rand *rand.Rand
+	// iteration is the number of the iteration of a stressed test this C
+	// runs, or zero.
+	iteration int
+	// clock tells the real time while the test runs in a synctest
+	// bubble, for timing its phases.
+	clock *realClock
+
+	// phase is the part of the test currently running, one of the
+	// phase constants, for reporting where a test got stuck.
+	phase atomic.Value
+	// abandoned is set once the runner has given up waiting for the
+	// test, after which its fixtures are no longer called.
+	abandoned atomic.Bool
+}
+
+// The phases of a suite test, and of the suite itself.
+const (
+	phaseSetUpSuite    = "SetUpSuite"
+	phaseTearDownSuite = "TearDownSuite"
+	phaseSetUpTest     = "SetUpTest"
+	phaseBody          = "test body"
+	phaseTearDownTest  = "TearDownTest"
+
+	phaseSetUpSubtest    = "SetUpSubtest"
+	phaseTearDownSubtest = "TearDownSubtest"
+)
+
+// inPhase calls f as the given phase of the test, recording how long it
+// took even if it stops the test.
+func (c *C) inPhase(phase string, f func()) {
+	c.Helper()
+	c.phase.Store(phase)
+	start := c.now()
+	defer func() {
+		d := c.now().Sub(start)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.timings == nil {
+			c.timings = make(map[string]time.Duration)
+		}
+		c.timings[phase] += d
+	}()
+	f()
 }
 
 // -----------------------------------------------------------------------
 // Some simple formatting helpers.
 
 func suiteName(suite any) string {
-	suiteType := reflect.TypeOf(suite)
-	if suiteType.Kind() == reflect.Ptr {
-		return suiteType.Elem().Name()
-	}
-	return suiteType.Name()
+	return suiteType(suite).Name()
+}
+
+// namedSuite is implemented by suites which stand in for, and are named
+// after, another type.
+type namedSuite interface {
+	namedType() reflect.Type
 }
 
 // -----------------------------------------------------------------------
@@ -117,9 +353,16 @@ func suiteName(suite any) string {
 
 type suiteRunner struct {
 	suite                     any
-	setUpSuite, tearDownSuite *methodType
-	setUpTest, tearDownTest   *methodType
+	setUpSuite, tearDownSuite chain
+	setUpTest, tearDownTest   chain
+	setUpSubtest              chain
+	tearDownSubtest           chain
 	tests                     []*methodType
+	benchmarks                []*methodType
+	fuzzers                   []*methodType
+	parallel                  bool
+	report                    *suiteReport
+	inRunAll                  bool
 }
 
 // Create a new suiteRunner able to run all methods in the given suite.
@@ -132,63 +375,215 @@ func newSuiteRunner(suite any) *suiteRunner {
 		suite: suite,
 		tests: make([]*methodType, 0, suiteNumMethods),
 	}
+	if p, ok := suite.(ParallelSuite); ok {
+		runner.parallel = p.Parallel()
+	}
+
+	layers := []reflect.Value{suiteValue}
+	if ch, ok := suite.(ChainedSuite); ok && ch.ChainFixtures() {
+		layers = suiteLayers(suiteValue)
+	}
+	for _, layer := range layers {
+		runner.setUpSuite = append(runner.setUpSuite, layerMethod(layer, "SetUpSuite", len(layers) > 1))
+		runner.tearDownSuite = append(runner.tearDownSuite, layerMethod(layer, "TearDownSuite", len(layers) > 1))
+		runner.setUpTest = append(runner.setUpTest, layerMethod(layer, "SetUpTest", len(layers) > 1))
+		runner.tearDownTest = append(runner.tearDownTest, layerMethod(layer, "TearDownTest", len(layers) > 1))
+		runner.setUpSubtest = append(runner.setUpSubtest, layerMethod(layer, "SetUpSubtest", len(layers) > 1))
+		runner.tearDownSubtest = append(runner.tearDownSubtest, layerMethod(layer, "TearDownSubtest", len(layers) > 1))
+	}
 
 	for i := 0; i != suiteNumMethods; i++ {
 		method := newMethod(suiteValue, i)
-		switch method.Info.Name {
-		case "SetUpSuite":
-			runner.setUpSuite = method
-		case "TearDownSuite":
-			runner.tearDownSuite = method
-		case "SetUpTest":
-			runner.setUpTest = method
-		case "TearDownTest":
-			runner.tearDownTest = method
-		default:
-			if !strings.HasPrefix(method.Info.Name, "Test") {
-				continue
-			}
+		switch {
+		case strings.HasPrefix(method.Info.Name, "Test"):
 			runner.tests = append(runner.tests, method)
+		case strings.HasPrefix(method.Info.Name, "Benchmark"):
+			runner.benchmarks = append(runner.benchmarks, method)
+		case strings.HasPrefix(method.Info.Name, "Fuzz"):
+			runner.fuzzers = append(runner.fuzzers, method)
 		}
 	}
+	runner.shuffleTests()
 	return runner
 }
 
+// suiteLayers returns the given suite value followed by the exported
+// structs embedded in it, directly or indirectly, innermost first. Each
+// embedded struct is returned as a pointer, and nil embedded pointers are
+// left out.
+func suiteLayers(suite reflect.Value) []reflect.Value {
+	value := suite
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return []reflect.Value{suite}
+	}
+	var layers []reflect.Value
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.Anonymous || !field.IsExported() {
+			continue
+		}
+		embedded := value.Field(i)
+		switch {
+		case embedded.Kind() == reflect.Struct && embedded.CanAddr():
+			layers = append(layers, suiteLayers(embedded.Addr())...)
+		case embedded.Kind() == reflect.Ptr && !embedded.IsNil() &&
+			embedded.Elem().Kind() == reflect.Struct:
+			layers = append(layers, suiteLayers(embedded)...)
+		}
+	}
+	return append(layers, suite)
+}
+
+// layerMethod returns the named method of the given suite layer, or nil if
+// it has none. If own is true, methods promoted from embedded structs are
+// ignored, as those are found in their own layer.
+func layerMethod(layer reflect.Value, name string, own bool) *methodType {
+	method, ok := layer.Type().MethodByName(name)
+	if !ok {
+		return nil
+	}
+	if own && !declaresMethod(layer.Type(), name) {
+		return nil
+	}
+	return newMethod(layer, method.Index)
+}
+
+// declaresMethod reports whether the named method is declared on t itself,
+// or on the type t points to, rather than promoted from an embedded field.
+func declaresMethod(t reflect.Type, name string) bool {
+	types := []reflect.Type{t}
+	if t.Kind() == reflect.Ptr {
+		types = append(types, t.Elem())
+	}
+	for _, t := range types {
+		method, ok := t.MethodByName(name)
+		if !ok {
+			continue
+		}
+		// The compiler generates wrappers for promoted methods, as it does
+		// for pointer receiver versions of value receiver methods.
+		fn := runtime.FuncForPC(method.Func.Pointer())
+		if file, _ := fn.FileLine(fn.Entry()); file != "<autogenerated>" {
+			return true
+		}
+	}
+	return false
+}
+
 // Run all methods in the given suite.
 func (runner *suiteRunner) run(t *testing.T) {
 	c := C{T: t, startTime: time.Now()}
-
-	setup := false
-	teardown := func() {
-		runner.tearDownSuite.Call(&c)
+	if reporting() {
+		// Registered first, so that the report includes TearDownSuite.
+		runner.report = newSuiteReport(t, runner.suite)
+		runner.report.slowest = !runner.inRunAll
+		t.Cleanup(func() {
+			runner.report.finish(&c, time.Since(c.startTime))
+		})
 	}
-	teardownOnSetupFail := func() {
-		if setup {
-			return
-		}
-		if c.Skipped() {
-			return
+	t.Cleanup(func() {
+		checkBudgets(&c, suitePhases)
+	})
+	runner.validateSuite(&c, entryRun)
+	if shuffleFlag.enabled {
+		fmt.Fprintf(t.Output(), "tests shuffled with -tc.shuffle=%d\n", shuffleFlag.seed)
+	}
+	shard, sharded, err := activeShard()
+	if err != nil {
+		c.Fatal(err)
+	}
+	if reason := runner.unmetRequirements(t.Output(), ""); reason != "" {
+		c.skipNow(reason)
+	}
+
+	// Worked out before the suite's fixtures run, so that they are not
+	// run at all if every test is skipped.
+	selections, selected := runner.selectTests(shard, sharded)
+	if !selected && len(runner.tests) > 0 {
+		for _, test := range runner.tests {
+			t.Run(test.Info.Name, func(t *testing.T) {
+				runner.skipTest(t, test, selections[test.Info.Name].skip)
+			})
 		}
-		teardown()
+		c.skipNow("no tests selected to run, so the suite's fixtures are not run")
 	}
 
-	// N.B. Teardown must always happen, even if the setup fails
-	// but must be ordered after setup cleanups.
-	t.Cleanup(teardownOnSetupFail)
-	runner.setUpSuite.Call(&c)
-	setup = true
-	t.Cleanup(teardown)
+	runBetween(&c, func(called *int) {
+		c.inPhase(phaseSetUpSuite, func() {
+			runner.setUpSuite.setUp(&c, called)
+		})
+	}, func(called int) {
+		c.inPhase(phaseTearDownSuite, func() {
+			runner.tearDownSuite.tearDown(&c, called)
+		})
+	}, nil)
 
 	for _, test := range runner.tests {
 		t.Run(test.Info.Name, func(t *testing.T) {
-			runner.runTest(t, test)
+			sel := selections[test.Info.Name]
+			if sel.skip != "" {
+				runner.skipTest(t, test, sel.skip)
+			}
+			io.WriteString(t.Output(), sel.notes)
+			if !isTableTest(test) {
+				runner.runParallelTest(t, test)
+				return
+			}
+			cases, err := runner.testCases(test)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, tcase := range cases {
+				t.Run(tcase.name, func(t *testing.T) {
+					runner.runParallelTest(t, test, tcase.value)
+				})
+			}
 		})
 	}
 }
 
+// runParallelTest runs the test method with the given arguments, in
+// parallel with the suite's other tests if the suite asked for that.
+func (runner *suiteRunner) runParallelTest(t *testing.T, method *methodType, args ...reflect.Value) {
+	if !runner.parallel {
+		runner.runTest(t, method, args...)
+		return
+	}
+	// Parallel tests only start once the suite's run function has
+	// returned, but the suite teardown is a cleanup of its t, so it still
+	// waits for all of them to complete.
+	t.Parallel()
+	clone := runner.clone()
+	clone.runTest(t, method.bind(reflect.ValueOf(clone.suite)), args...)
+}
+
+// clone returns a runner for a shallow copy of the suite value, so that
+// tests running in parallel don't share the state set up by SetUpTest.
+// Suites which aren't a pointer to a struct are not copied, nor are those
+// standing in for another type, such as RunSuite's, whose tests and
+// fixtures aren't methods of the suite and which keep no state.
+func (runner *suiteRunner) clone() *suiteRunner {
+	value := reflect.ValueOf(runner.suite)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return runner
+	}
+	if _, ok := runner.suite.(namedSuite); ok {
+		return runner
+	}
+	copied := reflect.New(value.Elem().Type())
+	copied.Elem().Set(value.Elem())
+	clone := newSuiteRunner(copied.Interface())
+	clone.report = runner.report
+	return clone
+}
+
 // Same as forkTest(), but wait for the test to finish before returning.
-func (runner *suiteRunner) runTest(t *testing.T, method *methodType) {
-	c := C{T: t, startTime: time.Now()}
+func (runner *suiteRunner) runTest(t *testing.T, method *methodType, args ...reflect.Value) {
+	start := time.Now()
+	c := C{T: t, startTime: start, runner: runner}
 
 	// Log out where this test is defined.
 	frame, _ := runtime.CallersFrames([]uintptr{method.PC()}).Next()
@@ -196,12 +591,102 @@ func (runner *suiteRunner) runTest(t *testing.T, method *methodType) {
 		fmt.Fprintf(t.Output(), "%s:%d\n", frame.File, frame.Line)
 	}
 
+	if runner.report != nil {
+		// Registered before anything else that can fail the test.
+		t.Cleanup(func() {
+			runner.report.addTest(&c, time.Since(start), runner.tags(method.Info.Name))
+		})
+	}
+	t.Cleanup(func() {
+		checkBudgets(&c, testPhases)
+	})
+
+	if ignores, ok := runner.leakIgnores(); ok {
+		// Registered before the fixtures, so that it runs after all
+		// of them and their cleanups.
+		before := runningGoroutines()
+		t.Cleanup(func() {
+			if !c.abandoned.Load() {
+				checkLeaks(&c, before, ignores)
+			}
+		})
+	}
+
+	if stressFlag.iterations > 0 {
+		runner.stress(&c, method, args...)
+		return
+	}
+
+	run := func() {
+		runner.runWithFixtures(&c, func() {
+			method.Call(&c, args...)
+		})
+	}
+	if retries, quarantined := runner.retryPolicy(method); retries > 0 || quarantined {
+		attempt := run
+		run = func() {
+			c.runRetried(retries, quarantined, attempt)
+		}
+	}
+	if runner.inBubble(method) {
+		bubbled := run
+		run = func() {
+			runInBubble(&c, bubbled)
+		}
+	}
+	if timeout := runner.testTimeout(method); timeout > 0 {
+		runWithTimeout(&c, method, timeout, run)
+		return
+	}
+	run()
+}
+
+// runWithFixtures calls body between SetUpTest and TearDownTest.
+func (runner *suiteRunner) runWithFixtures(c *C, body func()) {
+	runTestBetween(c, runner.setUpTest, runner.tearDownTest, phaseSetUpTest, phaseTearDownTest, body)
+}
+
+// runTestBetween calls body between the given set up and tear down
+// fixtures, run as the given phases of the test.
+func runTestBetween(c *C, setUp, tearDown chain, setUpPhase, tearDownPhase string, body func()) {
+	runBetween(c, func(called *int) {
+		c.inTestPhase(setUpPhase, func() {
+			setUp.setUp(c, called)
+		})
+	}, func(called int) {
+		if c.abandoned.Load() {
+			return
+		}
+		c.inTestPhase(tearDownPhase, func() {
+			tearDown.tearDown(c, called)
+		})
+	}, func() {
+		c.inTestPhase(phaseBody, func() {
+			ctx, cancel := context.WithCancel(c.Context())
+			c.ctx = ctx
+			defer func() {
+				cancel()
+				c.ctx = nil
+			}()
+			body()
+		})
+	})
+}
+
+// runBetween calls setUp and then body, if any, and arranges for tearDown
+// to be called once with the number of fixture layers setUp called. If
+// there is a body, the teardown happens as soon as it returns, and
+// otherwise when c's cleanups run, after any parallel subtests.
+func runBetween(c LikeC, setUp func(called *int), tearDown func(called int), body func()) {
 	setup := false
-	once := sync.Once{}
+	called := 0
+	// tornDown doesn't block, unlike a sync.Once, as a test abandoned by
+	// the watchdog may never return from its teardown.
+	var tornDown atomic.Bool
 	teardown := func() {
-		once.Do(func() {
-			runner.tearDownTest.Call(&c)
-		})
+		if tornDown.CompareAndSwap(false, true) {
+			tearDown(called)
+		}
 	}
 	teardownOnSetupFail := func() {
 		if setup {
@@ -217,10 +702,13 @@ func (runner *suiteRunner) runTest(t *testing.T, method *methodType) {
 	// but must be ordered after setup cleanups. If the test does
 	// not panic, then the teardown happens-before testing.T cleanup
 	// and context cancellation.
-	t.Cleanup(teardownOnSetupFail)
-	runner.setUpTest.Call(&c)
+	c.Cleanup(teardownOnSetupFail)
+	setUp(&called)
 	setup = true
-	t.Cleanup(teardown)
+	c.Cleanup(teardown)
+	if body == nil {
+		return
+	}
 	defer teardown()
-	method.Call(&c)
+	body()
 }
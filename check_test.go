@@ -30,10 +30,14 @@
 package tc_test
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -203,3 +207,733 @@ func (s *SkippedTestSuite) TearDownTest(c *tc.C) {
 func (s *SkippedTestSuite) TestShouldFail(c *tc.C) {
 	c.FailNow()
 }
+
+// -----------------------------------------------------------------------
+// Helper suite for testing parallel test methods.
+
+type parallelEvents struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (e *parallelEvents) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, event)
+}
+
+type ParallelHelper struct {
+	events *parallelEvents
+	name   string
+}
+
+func (s *ParallelHelper) Parallel() bool {
+	return true
+}
+
+func (s *ParallelHelper) SetUpSuite(c *tc.C) {
+	s.events = &parallelEvents{}
+	s.events.add("SetUpSuite")
+}
+
+func (s *ParallelHelper) TearDownSuite(c *tc.C) {
+	s.events.add("TearDownSuite")
+}
+
+func (s *ParallelHelper) SetUpTest(c *tc.C) {
+	s.name = c.TestName()
+}
+
+func (s *ParallelHelper) check(c *tc.C) {
+	// Yield so that the other test gets the chance to clobber s.name
+	// if the suite value were shared.
+	time.Sleep(10 * time.Millisecond)
+	c.Check(s.name, tc.Equals, c.TestName())
+	s.events.add(c.TestName())
+}
+
+func (s *ParallelHelper) Test1(c *tc.C) {
+	s.check(c)
+}
+
+func (s *ParallelHelper) Test2(c *tc.C) {
+	s.check(c)
+}
+
+// -----------------------------------------------------------------------
+// Helper suites for testing chained fixtures of embedded suites.
+
+type ChainBase struct {
+	calls  *[]string
+	failOn string
+}
+
+func (s *ChainBase) trace(name string, c *tc.C) {
+	*s.calls = append(*s.calls, name)
+	if name == s.failOn {
+		c.Fatal("failing " + name)
+	}
+}
+
+func (s *ChainBase) SetUpSuite(c *tc.C) {
+	s.calls = &[]string{}
+	s.trace("ChainBase.SetUpSuite", c)
+}
+
+func (s *ChainBase) TearDownSuite(c *tc.C) {
+	s.trace("ChainBase.TearDownSuite", c)
+}
+
+func (s *ChainBase) SetUpTest(c *tc.C) {
+	s.trace("ChainBase.SetUpTest", c)
+}
+
+func (s *ChainBase) TearDownTest(c *tc.C) {
+	s.trace("ChainBase.TearDownTest", c)
+}
+
+type ChainMiddle struct {
+	ChainBase
+}
+
+func (s *ChainMiddle) SetUpTest(c *tc.C) {
+	s.trace("ChainMiddle.SetUpTest", c)
+}
+
+func (s *ChainMiddle) TearDownTest(c *tc.C) {
+	s.trace("ChainMiddle.TearDownTest", c)
+}
+
+type ChainHelper struct {
+	ChainMiddle
+	chained bool
+}
+
+func (s *ChainHelper) ChainFixtures() bool {
+	return s.chained
+}
+
+func (s *ChainHelper) SetUpTest(c *tc.C) {
+	s.trace("ChainHelper.SetUpTest", c)
+}
+
+func (s *ChainHelper) Test1(c *tc.C) {
+	s.trace("Test1", c)
+}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing test timeouts.
+
+type TimeoutHelper struct {
+	hangOn string
+}
+
+func (s *TimeoutHelper) TimeoutFor(method string) time.Duration {
+	if method == "TestNoTimeout" {
+		return -1
+	}
+	return 100 * time.Millisecond
+}
+
+func (s *TimeoutHelper) hang(name string) {
+	if name == s.hangOn {
+		time.Sleep(time.Hour)
+	}
+}
+
+func (s *TimeoutHelper) SetUpTest(c *tc.C) {
+	s.hang("SetUpTest")
+}
+
+func (s *TimeoutHelper) TearDownTest(c *tc.C) {
+	s.hang("TearDownTest")
+}
+
+func (s *TimeoutHelper) Test1(c *tc.C) {
+	s.hang("Test1")
+}
+
+func (s *TimeoutHelper) Test2(c *tc.C) {
+	c.Assert(s.hangOn, tc.Not(tc.Equals), "Test2")
+}
+
+func (s *TimeoutHelper) TestLate(c *tc.C) {
+	if s.hangOn == "TestLate" {
+		time.Sleep(300 * time.Millisecond)
+		c.Log("logged after timing out")
+		c.Check(1, tc.Equals, 2)
+		c.Errorf("failed after timing out")
+	}
+}
+
+func (s *TimeoutHelper) TearDownSuite(c *tc.C) {
+	if s.hangOn == "TestLate" {
+		// Gives the abandoned TestLate time to report.
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing goroutine leak detection.
+
+type LeakHelper struct {
+	stop chan struct{}
+}
+
+func (s *LeakHelper) IgnoreLeaks() []string {
+	return []string{"ignoredWorker"}
+}
+
+func (s *LeakHelper) SetUpSuite(c *tc.C) {
+	s.stop = make(chan struct{})
+}
+
+func (s *LeakHelper) TearDownSuite(c *tc.C) {
+	close(s.stop)
+}
+
+func (s *LeakHelper) leakedWorker() {
+	<-s.stop
+}
+
+func (s *LeakHelper) ignoredWorker() {
+	<-s.stop
+}
+
+func (s *LeakHelper) TestLeak(c *tc.C) {
+	go s.leakedWorker()
+}
+
+func (s *LeakHelper) TestIgnoredLeak(c *tc.C) {
+	go s.ignoredWorker()
+}
+
+func (s *LeakHelper) TestSlowGoroutine(c *tc.C) {
+	go time.Sleep(50 * time.Millisecond)
+}
+
+func (s *LeakHelper) TestStoppedInCleanup(c *tc.C) {
+	done := make(chan struct{})
+	go func() {
+		<-done
+	}()
+	c.Cleanup(func() {
+		close(done)
+	})
+}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing reports of suite results.
+
+type ReportHelper struct{}
+
+func (s *ReportHelper) SetUpTest(c *tc.C) {}
+
+func (s *ReportHelper) TestPass(c *tc.C) {}
+
+func (s *ReportHelper) TestFail(c *tc.C) {
+	c.Check(1, tc.Equals, 2)
+}
+
+func (s *ReportHelper) TestSkip(c *tc.C) {
+	c.Skip("not today")
+}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing shuffled test order.
+
+type ShuffleHelper struct{}
+
+func (s *ShuffleHelper) TestA(c *tc.C) {}
+func (s *ShuffleHelper) TestB(c *tc.C) {}
+func (s *ShuffleHelper) TestC(c *tc.C) {}
+func (s *ShuffleHelper) TestD(c *tc.C) {}
+func (s *ShuffleHelper) TestE(c *tc.C) {}
+func (s *ShuffleHelper) TestF(c *tc.C) {}
+func (s *ShuffleHelper) TestG(c *tc.C) {}
+func (s *ShuffleHelper) TestH(c *tc.C) {}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing timing reports and budgets.
+
+type TimingHelper struct{}
+
+func (s *TimingHelper) SetUpTest(c *tc.C) {
+	if strings.HasSuffix(c.TestName(), "TestSlowFixture") {
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func (s *TimingHelper) TestSlow(c *tc.C) {
+	time.Sleep(100 * time.Millisecond)
+}
+
+func (s *TimingHelper) TestSlowFixture(c *tc.C) {}
+
+func (s *TimingHelper) TestFast(c *tc.C) {}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing requirements.
+
+type RequiresHelper struct {
+	suiteOS string
+}
+
+func (s *RequiresHelper) Requirements(method string) []tc.Requirement {
+	switch method {
+	case "":
+		if s.suiteOS != "" {
+			return []tc.Requirement{tc.RequireOS(s.suiteOS)}
+		}
+	case "TestUnmet":
+		return []tc.Requirement{
+			tc.RequireEnv("TC_NO_SUCH_VARIABLE"),
+			tc.RequireBinary("tc-no-such-binary"),
+			tc.RequireOS("plan9"),
+		}
+	case "TestMet":
+		return []tc.Requirement{tc.RequireEnv("PATH"), tc.RequireOS(runtime.GOOS)}
+	}
+	return nil
+}
+
+func (s *RequiresHelper) SetUpSuite(c *tc.C) {
+	c.Log("SetUpSuite")
+}
+
+func (s *RequiresHelper) SetUpTest(c *tc.C) {
+	c.Log("SetUpTest")
+}
+
+func (s *RequiresHelper) TestUnmet(c *tc.C) {
+	c.Fail()
+}
+
+func (s *RequiresHelper) TestMet(c *tc.C) {}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing methods taking a context or returning an error.
+
+type stackError struct{}
+
+func (stackError) Error() string {
+	return "failed with a stack"
+}
+
+func (stackError) StackTrace() []string {
+	return []string{"first.go:1", "second.go:2"}
+}
+
+type SignatureHelper struct {
+	ctx               context.Context
+	cancelledBeforeTD bool
+	cases             []int
+}
+
+func (s *SignatureHelper) TearDownTest(c *tc.C) {
+	if s.ctx != nil {
+		s.cancelledBeforeTD = s.ctx.Err() != nil && c.Context().Err() == nil
+	}
+}
+
+func (s *SignatureHelper) TestContext(ctx context.Context, c *tc.C) {
+	c.Check(ctx.Err(), tc.IsNil)
+	s.ctx = ctx
+}
+
+func (s *SignatureHelper) CasesForTestContextCases() []int {
+	return []int{1, 2}
+}
+
+func (s *SignatureHelper) TestContextCases(ctx context.Context, c *tc.C, n int) {
+	s.cases = append(s.cases, n)
+}
+
+func (s *SignatureHelper) TestNilError(c *tc.C) error {
+	return nil
+}
+
+func (s *SignatureHelper) TestError(c *tc.C) error {
+	if *helperFailFlag == "" {
+		return nil
+	}
+	return stackError{}
+}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing fixtures returning errors.
+
+type FixtureErrorHelper struct{}
+
+func (s *FixtureErrorHelper) SetUpSuite(c *tc.C) error {
+	if *helperFailFlag == "SetUpSuite" {
+		return fmt.Errorf("cannot set up suite")
+	}
+	return nil
+}
+
+func (s *FixtureErrorHelper) SetUpTest(c *tc.C) error {
+	if *helperFailFlag == "SetUpTest" {
+		return fmt.Errorf("cannot set up test")
+	}
+	return nil
+}
+
+func (s *FixtureErrorHelper) TearDownTest(c *tc.C) error {
+	c.Log("TearDownTest ran")
+	if *helperFailFlag == "TearDownTest" {
+		return fmt.Errorf("cannot tear down test")
+	}
+	return nil
+}
+
+func (s *FixtureErrorHelper) TearDownSuite(c *tc.C) {
+	c.Log("TearDownSuite ran")
+}
+
+func (s *FixtureErrorHelper) TestBody(c *tc.C) error {
+	c.Log("TestBody ran")
+	if *helperFailFlag == "TestBody" {
+		return fmt.Errorf("cannot run test")
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing tags.
+
+type TagHelper struct{}
+
+func (s *TagHelper) Tags() map[string][]string {
+	return map[string][]string{
+		"":            {"unit"},
+		"TestSlow":    {"slow"},
+		"TestDB":      {"db"},
+		"TestFlakyDB": {"db", "flaky"},
+	}
+}
+
+func (s *TagHelper) TestSlow(c *tc.C)    {}
+func (s *TagHelper) TestDB(c *tc.C)      {}
+func (s *TagHelper) TestFlakyDB(c *tc.C) {}
+func (s *TagHelper) TestPlain(c *tc.C)   {}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing retries and quarantine.
+
+type RetryHelper struct {
+	runs map[string]int
+}
+
+func (s *RetryHelper) RetriesFor(method string) int {
+	if method == "TestOnce" {
+		return -1
+	}
+	return 2
+}
+
+func (s *RetryHelper) Quarantined(method string) bool {
+	return method == "TestQuarantined"
+}
+
+func (s *RetryHelper) SetUpTest(c *tc.C) {
+	if s.runs == nil {
+		s.runs = make(map[string]int)
+	}
+	s.runs[c.TestName()]++
+	c.Logf("set up attempt %d", s.runs[c.TestName()])
+}
+
+func (s *RetryHelper) TearDownTest(c *tc.C) {
+	c.Logf("tear down attempt %d", s.runs[c.TestName()])
+}
+
+func (s *RetryHelper) TestFlaky(c *tc.C) {
+	c.Assert(s.runs[c.TestName()], tc.Equals, 2)
+}
+
+func (s *RetryHelper) TestBroken(c *tc.C) {
+	c.Errorf("broken on attempt %d", s.runs[c.TestName()])
+}
+
+func (s *RetryHelper) TestQuarantined(c *tc.C) {
+	c.Fatal("always fails")
+}
+
+func (s *RetryHelper) TestOnce(c *tc.C) {
+	c.Fail()
+}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing that retried attempts start afresh.
+
+type RetryEnvHelper struct {
+	attempts int
+	wd       string
+}
+
+func (s *RetryEnvHelper) RetriesFor(method string) int {
+	return 1
+}
+
+func (s *RetryEnvHelper) TestEnv(c *tc.C) {
+	s.attempts++
+	c.Logf("attempt %d: TC_RETRY_ENV=%q", s.attempts, os.Getenv("TC_RETRY_ENV"))
+	wd, err := os.Getwd()
+	c.Assert(err, tc.IsNil)
+	if s.attempts == 1 {
+		s.wd = wd
+		c.Setenv("TC_RETRY_ENV", "dirty")
+		c.Chdir(c.MkDir())
+		c.Fail()
+		return
+	}
+	c.Check(wd, tc.Equals, s.wd)
+}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing tests run in a synctest bubble.
+
+type SyncTestHelper struct {
+	setUp time.Time
+}
+
+func (s *SyncTestHelper) SyncTest(method string) bool {
+	return method != "TestReal"
+}
+
+func (s *SyncTestHelper) TimeoutFor(method string) time.Duration {
+	return 5 * time.Second
+}
+
+func (s *SyncTestHelper) SetUpTest(c *tc.C) {
+	s.setUp = time.Now()
+}
+
+func (s *SyncTestHelper) TearDownTest(c *tc.C) {
+	c.Logf("torn down after %v", time.Since(s.setUp))
+}
+
+func (s *SyncTestHelper) TestSleep(c *tc.C) {
+	start := time.Now()
+	c.Check(start, tc.Almost, s.setUp)
+	ticks := 0
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for range ticker.C {
+		if ticks++; ticks == 24 {
+			break
+		}
+	}
+	c.Check(time.Since(start), tc.Equals, 24*time.Hour)
+	c.Check(time.Now(), tc.TimeBetween(start.Add(24*time.Hour), start.Add(24*time.Hour)))
+}
+
+func (s *SyncTestHelper) TestSkip(c *tc.C) {
+	c.Skip("skipped in the bubble")
+}
+
+func (s *SyncTestHelper) TestFail(c *tc.C) {
+	time.Sleep(time.Minute)
+	c.Errorf("failed after %v", time.Since(s.setUp))
+}
+
+func (s *SyncTestHelper) TestReal(c *tc.C) {
+	c.Check(time.Since(s.setUp), tc.DurationLessThan, time.Minute)
+}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing synctest bubbles that never finish.
+
+type SyncTestHangHelper struct{}
+
+func (s *SyncTestHangHelper) SyncTest(method string) bool {
+	return method != "TestNext"
+}
+
+func (s *SyncTestHangHelper) TimeoutFor(method string) time.Duration {
+	if method == "TestDeadlock" {
+		// A deadlocked bubble fails without the watchdog.
+		return -1
+	}
+	return 200 * time.Millisecond
+}
+
+func (s *SyncTestHangHelper) TestDeadlock(c *tc.C) {
+	<-make(chan struct{})
+}
+
+func (s *SyncTestHangHelper) TestHang(c *tc.C) {
+	var mu sync.Mutex
+	mu.Lock()
+	mu.Lock()
+}
+
+func (s *SyncTestHangHelper) TestNext(c *tc.C) {}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing stress runs.
+
+type StressHelper struct {
+	failAt string
+	runs   map[string]*int64
+	local  int
+}
+
+func (s *StressHelper) SetUpSuite(c *tc.C) {
+	s.runs = map[string]*int64{"TestCount": new(int64), "TestPass": new(int64)}
+}
+
+func (s *StressHelper) SetUpTest(c *tc.C) {
+	// Every iteration has its own copy of the suite.
+	c.Assert(s.local, tc.Equals, 0)
+	s.local++
+}
+
+func (s *StressHelper) TestCount(c *tc.C) {
+	n := atomic.AddInt64(s.runs["TestCount"], 1)
+	c.Check(fmt.Sprint(n), tc.Not(tc.Equals), s.failAt)
+}
+
+func (s *StressHelper) TestPass(c *tc.C) {
+	atomic.AddInt64(s.runs["TestPass"], 1)
+}
+
+func (s *StressHelper) TearDownSuite(c *tc.C) {
+	c.Logf("TestCount ran %d times, TestPass ran %d times",
+		atomic.LoadInt64(s.runs["TestCount"]), atomic.LoadInt64(s.runs["TestPass"]))
+}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing that stress iterations are run as tests are.
+
+type StressRunHelper struct {
+	mu    *sync.Mutex
+	drawn map[int64]bool
+	hangs *int64
+}
+
+func (s *StressRunHelper) SetUpSuite(c *tc.C) {
+	s.mu = &sync.Mutex{}
+	s.drawn = make(map[int64]bool)
+	s.hangs = new(int64)
+}
+
+func (s *StressRunHelper) SyncTest(method string) bool {
+	return method == "TestSleep"
+}
+
+func (s *StressRunHelper) TimeoutFor(method string) time.Duration {
+	if method == "TestHang" {
+		return 100 * time.Millisecond
+	}
+	return 5 * time.Second
+}
+
+func (s *StressRunHelper) TestSleep(c *tc.C) {
+	start := time.Now()
+	time.Sleep(time.Hour)
+	c.Check(time.Since(start), tc.Equals, time.Hour)
+}
+
+func (s *StressRunHelper) TestRand(c *tc.C) {
+	n := c.Rand().Int63()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.Check(s.drawn[n], tc.IsFalse, tc.Commentf("drew %d again", n))
+	s.drawn[n] = true
+}
+
+func (s *StressRunHelper) TestHang(c *tc.C) {
+	if atomic.AddInt64(s.hangs, 1) == 2 {
+		select {}
+	}
+}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing seeded random numbers.
+
+type RandHelper struct{}
+
+func (s *RandHelper) TestFail(c *tc.C) {
+	c.Assert(c.Rand(), tc.Equals, c.Rand())
+	c.Errorf("drew %d", c.Rand().Int63())
+}
+
+func (s *RandHelper) TestPass(c *tc.C) {
+	c.Logf("drew %d", c.Rand().Int63())
+}
+
+func (s *RandHelper) TestUnused(c *tc.C) {
+	c.Fail()
+}
+
+// -----------------------------------------------------------------------
+// Helper suite for testing IsolationSuite.
+
+type IsolationHelper struct {
+	tc.IsolationSuite
+	home string
+	wd   string
+	args int
+}
+
+func (s *IsolationHelper) SetUpSuite(c *tc.C) {
+	s.KeepEnv = []string{"TC_ISOLATION_KEPT"}
+	s.home = os.Getenv("HOME")
+	s.wd, _ = os.Getwd()
+	s.args = len(os.Args)
+}
+
+func (s *IsolationHelper) TestIsolated(c *tc.C) {
+	c.Check(os.Getenv("TC_ISOLATION_SECRET"), tc.Equals, "")
+	c.Check(os.Getenv("TC_ISOLATION_KEPT"), tc.Equals, "kept")
+	c.Check(os.Getenv("PATH"), tc.Not(tc.Equals), "")
+	c.Check(os.Getenv("HOME"), tc.Not(tc.Equals), s.home)
+	wd, err := os.Getwd()
+	c.Assert(err, tc.IsNil)
+	c.Check(wd, tc.Not(tc.Equals), s.wd)
+	c.Check(len(os.Args), tc.Equals, s.args)
+	os.Args = append(os.Args, "changed")
+	c.Setenv("TC_ISOLATION_SET", "set")
+	c.Chdir(c.MkDir())
+}
+
+func (s *IsolationHelper) TestLeaks(c *tc.C) {
+	os.Setenv("TC_ISOLATION_LEAKED", "leaked")
+	os.Chdir(s.wd)
+}
+
+func (s *IsolationHelper) TestRestored(c *tc.C) {
+	c.Check(os.Getenv("TC_ISOLATION_LEAKED"), tc.Equals, "")
+	c.Check(os.Getenv("TC_ISOLATION_SET"), tc.Equals, "")
+	c.Check(len(os.Args), tc.Equals, s.args)
+	wd, err := os.Getwd()
+	c.Assert(err, tc.IsNil)
+	c.Check(wd, tc.Not(tc.Equals), s.wd)
+}
+
+func (s *IsolationHelper) TearDownSuite(c *tc.C) {
+	c.Check(os.Getenv("TC_ISOLATION_SECRET"), tc.Equals, "secret")
+	c.Check(os.Getenv("HOME"), tc.Equals, s.home)
+	wd, _ := os.Getwd()
+	c.Check(wd, tc.Equals, s.wd)
+}
+
+// Helper suite for testing that IsolationSuite refuses parallel tests.
+
+type IsolationParallelHelper struct {
+	tc.IsolationSuite
+}
+
+func (s *IsolationParallelHelper) Parallel() bool {
+	return true
+}
+
+func (s *IsolationParallelHelper) Test1(c *tc.C) {
+	c.Log("Test1 ran")
+}
+
+func (s *IsolationParallelHelper) Test2(c *tc.C) {
+	c.Log("Test2 ran")
+}
@@ -11,3 +11,15 @@ func Indent(s, with string) string {
 func (c *C) FakeSkip(reason string) {
 	c.reason = reason
 }
+
+func Validate(suite any) []string {
+	return newSuiteRunner(suite).validate(true, entryRun)
+}
+
+func ValidateFor(entry string, suite any) []string {
+	return newSuiteRunner(suite).validate(true, entryPoint(entry))
+}
+
+func ListTests(suite any) []listedTest {
+	return newSuiteRunner(suite).listTests()
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// F wraps a testing.F and implements LikeC. It is passed to fuzz methods,
+// and to SetUpSuite and TearDownSuite methods taking LikeC, when a suite is
+// run with RunF.
+type F struct {
+	*testing.F
+	likeC
+
+	runner *suiteRunner
+}
+
+var _ LikeC = (*F)(nil)
+
+func newF(f *testing.F, runner *suiteRunner) *F {
+	return &F{F: f, likeC: likeC{&TBC{TB: f}}, runner: runner}
+}
+
+// Fuzz runs the fuzz function ff, which must be of the form
+// func(*C, ...) where the remaining parameters are the types to be fuzzed,
+// as for testing.F.Fuzz. SetUpTest and TearDownTest are run around every
+// call of ff, with the same *C.
+func (f *F) Fuzz(ff any) {
+	f.Helper()
+	fn := reflect.ValueOf(ff)
+	fnType := fn.Type()
+	if fnType.Kind() != reflect.Func || fnType.NumIn() < 1 ||
+		fnType.In(0) != cType || fnType.NumOut() != 0 {
+		panic("tc: F.Fuzz function must be of the form func(*tc.C, ...)")
+	}
+
+	in := []reflect.Type{testingT}
+	for i := 1; i < fnType.NumIn(); i++ {
+		in = append(in, fnType.In(i))
+	}
+	wrapper := reflect.MakeFunc(reflect.FuncOf(in, nil, false), func(args []reflect.Value) []reflect.Value {
+		c := &C{T: args[0].Interface().(*testing.T), startTime: time.Now(), runner: f.runner}
+		f.runner.runWithFixtures(c, func() {
+			fn.Call(append([]reflect.Value{reflect.ValueOf(c)}, args[1:]...))
+		})
+		return nil
+	})
+	f.F.Fuzz(wrapper.Interface())
+}
+
+// RunF runs a method starting with the Fuzz prefix in the provided suite as
+// the fuzz target of f. If the suite has more than one such method, the one
+// named after the fuzz test is used. The method must take a *F, on which
+// it can add to the seed corpus and then call Fuzz.
+//
+// SetUpSuite and TearDownSuite are run once around the fuzz method and are
+// passed a *F, so fixtures shared with tests run by Run must take a LikeC.
+// SetUpTest and TearDownTest are run around every fuzz input.
+func RunF(f *testing.F, suite any) {
+	f.Helper()
+	runner := newSuiteRunner(suite)
+	runner.runFuzz(f)
+}
+
+// Run the fuzz method in the given suite.
+func (runner *suiteRunner) runFuzz(f *testing.F) {
+	c := newF(f, runner)
+	runner.validateSuite(c, entryRunF)
+
+	var method *methodType
+	switch len(runner.fuzzers) {
+	case 0:
+		c.Fatalf("suite %s has no fuzz methods", suiteName(runner.suite))
+	case 1:
+		method = runner.fuzzers[0]
+	default:
+		var names []string
+		for _, fuzzer := range runner.fuzzers {
+			if fuzzer.Info.Name == f.Name() {
+				method = fuzzer
+			}
+			names = append(names, fuzzer.Info.Name)
+		}
+		if method == nil {
+			c.Fatalf("suite %s has several fuzz methods, name the fuzz test after one of: %s",
+				suiteName(runner.suite), strings.Join(names, ", "))
+		}
+	}
+
+	runBetween(c, func(called *int) {
+		runner.setUpSuite.setUp(c, called)
+	}, func(called int) {
+		runner.tearDownSuite.tearDown(c, called)
+	}, nil)
+
+	method.Call(c)
+}
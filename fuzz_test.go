@@ -0,0 +1,57 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc_test
+
+import (
+	"strings"
+
+	"github.com/juju/tc"
+)
+
+type FuzzS struct{}
+
+var _ = tc.InternalSuite(&FuzzS{})
+
+type fuzzHelper struct {
+	calls []string
+	input string
+}
+
+func (s *fuzzHelper) SetUpSuite(c tc.LikeC) {
+	s.calls = append(s.calls, "SetUpSuite")
+}
+
+func (s *fuzzHelper) TearDownSuite(c tc.LikeC) {
+	s.calls = append(s.calls, "TearDownSuite")
+	c.Logf("calls: %s", strings.Join(s.calls, " "))
+}
+
+func (s *fuzzHelper) SetUpTest(c *tc.C) {
+	s.calls = append(s.calls, "SetUpTest")
+	s.input = "unset"
+}
+
+func (s *fuzzHelper) TearDownTest(c *tc.C) {
+	s.calls = append(s.calls, "TearDownTest")
+}
+
+func (s *fuzzHelper) FuzzRepeat(f *tc.F) {
+	f.Add("a")
+	f.Add("b")
+	f.Fuzz(func(c *tc.C, input string) {
+		c.Check(s.input, tc.Equals, "unset")
+		s.input = input
+		s.calls = append(s.calls, "Fuzz("+input+")")
+		c.Check(strings.Repeat(input, 2), tc.HasLen, 2*len(input))
+	})
+}
+
+func (s *FuzzS) TestRunF(c *tc.C) {
+	exitCode, output := runHelper("FuzzHelperSuite", "fuzzHelper")
+	c.Check(exitCode, tc.Equals, 0)
+	c.Check(strings.Join(output, "\n"), tc.Contains, "calls: SetUpSuite "+
+		"SetUpTest Fuzz(a) TearDownTest "+
+		"SetUpTest Fuzz(b) TearDownTest "+
+		"TearDownSuite")
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// goroutine is a single goroutine taken from a dump of all goroutines.
+type goroutine struct {
+	id    int
+	stack string
+}
+
+// goroutines returns every goroutine currently running.
+func goroutines() []goroutine {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	var result []goroutine
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		stack = strings.TrimSpace(stack)
+		header, _, _ := strings.Cut(stack, "\n")
+		fields := strings.Fields(header)
+		if len(fields) < 2 || fields[0] != "goroutine" {
+			continue
+		}
+		id, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		result = append(result, goroutine{id: id, stack: stack})
+	}
+	return result
+}
+
+// systemPackages are the packages whose goroutines are of no interest when
+// looking at what a test is doing. The main package is the generated test
+// main function.
+var systemPackages = []string{
+	"runtime", "testing", "os/signal", "reflect", "sync",
+	"github.com/juju/tc", "main",
+}
+
+// functions returns the names of the functions on the goroutine's stack,
+// innermost first, excluding the function that created it.
+func (g goroutine) functions() []string {
+	var names []string
+	for _, line := range strings.Split(g.stack, "\n")[1:] {
+		if line == "" || line[0] == '\t' || strings.HasPrefix(line, "created by ") {
+			continue
+		}
+		if i := strings.LastIndex(line, "("); i > 0 {
+			line = line[:i]
+		}
+		names = append(names, line)
+	}
+	return names
+}
+
+// isSystem reports whether the goroutine is of no interest when looking at
+// what a test is doing. That is one running only code from the runtime, the
+// testing package or this package's runner, or one waiting in the testing
+// package, as a test's goroutine does while its subtests run.
+func (g goroutine) isSystem() bool {
+	functions := g.functions()
+	for _, fn := range functions {
+		if pkg := funcPackage(fn); pkg != "runtime" {
+			if pkg == "testing" {
+				return true
+			}
+			break
+		}
+	}
+	for _, fn := range functions {
+		pkg := funcPackage(fn)
+		system := strings.HasPrefix(pkg, "internal/")
+		for _, systemPkg := range systemPackages {
+			system = system || pkg == systemPkg
+		}
+		if !system {
+			return false
+		}
+	}
+	return true
+}
+
+// funcPackage returns the import path of the package of the named function.
+func funcPackage(fn string) string {
+	slash := strings.LastIndex(fn, "/")
+	if dot := strings.Index(fn[slash+1:], "."); dot >= 0 {
+		return fn[:slash+1+dot]
+	}
+	return fn
+}
+
+// inBubble reports whether the goroutine belongs to a synctest bubble.
+func (g goroutine) inBubble() bool {
+	header, _, _ := strings.Cut(g.stack, "\n")
+	return strings.Contains(header, "synctest bubble")
+}
+
+// formatGoroutines returns the stacks of the given goroutines, ready for
+// printing.
+func formatGoroutines(gs []goroutine) string {
+	stacks := make([]string, len(gs))
+	for i, g := range gs {
+		stacks[i] = g.stack
+	}
+	return strings.Join(stacks, "\n\n")
+}
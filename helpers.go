@@ -69,11 +69,114 @@ func (l *logger) Output(calldepth int, s string) error {
 
 	_, file, line, _ := runtime.Caller(calldepth)
 	file = filepath.Base(file)
-	fmt.Fprintf(l.T.Output(), "%s:%d: %d:%02d.%03d %s\n",
+	fmt.Fprintf(l.C.Output(), "%s:%d: %d:%02d.%03d %s\n",
 		file, line, min, sec%60, msec%1000, s)
 	return nil
 }
 
+// -----------------------------------------------------------------------
+// Recording of failures and skips, so they can be reported.
+
+// Error is equivalent to Log followed by Fail.
+func (c *C) Error(args ...any) {
+	c.Helper()
+	c.recordFailure(fmt.Sprintln(args...))
+	if a := c.current(); a != nil {
+		c.logAttempt(a, fmt.Sprintln(args...))
+		c.Fail()
+		return
+	}
+	c.T.Error(args...)
+}
+
+// Errorf is equivalent to Logf followed by Fail.
+func (c *C) Errorf(format string, args ...any) {
+	c.Helper()
+	c.recordFailure(fmt.Sprintf(format, args...))
+	if a := c.current(); a != nil {
+		c.logAttempt(a, fmt.Sprintf(format, args...))
+		c.Fail()
+		return
+	}
+	c.T.Errorf(format, args...)
+}
+
+// Fatal is equivalent to Log followed by FailNow.
+func (c *C) Fatal(args ...any) {
+	c.Helper()
+	c.recordFailure(fmt.Sprintln(args...))
+	if a := c.current(); a != nil {
+		c.logAttempt(a, fmt.Sprintln(args...))
+		c.FailNow()
+		return
+	}
+	c.T.Fatal(args...)
+}
+
+// Fatalf is equivalent to Logf followed by FailNow.
+func (c *C) Fatalf(format string, args ...any) {
+	c.Helper()
+	c.recordFailure(fmt.Sprintf(format, args...))
+	if a := c.current(); a != nil {
+		c.logAttempt(a, fmt.Sprintf(format, args...))
+		c.FailNow()
+		return
+	}
+	c.T.Fatalf(format, args...)
+}
+
+// Skip is equivalent to Log followed by SkipNow.
+func (c *C) Skip(args ...any) {
+	c.Helper()
+	c.recordSkip(fmt.Sprintln(args...))
+	if a := c.current(); a != nil {
+		c.logAttempt(a, fmt.Sprintln(args...))
+		c.SkipNow()
+		return
+	}
+	c.T.Skip(args...)
+}
+
+// Skipf is equivalent to Logf followed by SkipNow.
+func (c *C) Skipf(format string, args ...any) {
+	c.Helper()
+	c.recordSkip(fmt.Sprintf(format, args...))
+	if a := c.current(); a != nil {
+		c.logAttempt(a, fmt.Sprintf(format, args...))
+		c.SkipNow()
+		return
+	}
+	c.T.Skipf(format, args...)
+}
+
+// skipNow skips the test for a reason that is printed directly, as it is
+// the runner and not any line of the test that decides to skip.
+func (c *C) skipNow(reason string) {
+	c.recordSkip(reason)
+	fmt.Fprintln(c.Output(), reason)
+	c.SkipNow()
+}
+
+// fail fails the test with a message that is printed directly, as it is
+// the runner and not any line of the test that found the failure.
+func (c *C) fail(message string) {
+	c.recordFailure(message)
+	fmt.Fprintln(c.Output(), message)
+	c.Fail()
+}
+
+func (c *C) recordFailure(message string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures = append(c.failures, strings.TrimSuffix(message, "\n"))
+}
+
+func (c *C) recordSkip(reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reason = strings.TrimSuffix(reason, "\n")
+}
+
 // -----------------------------------------------------------------------
 // Generic checks and assertions based on checkers.
 
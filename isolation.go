@@ -0,0 +1,140 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// isolatedEnv lists the environment variables an IsolationSuite keeps,
+// as the Go toolchain and the operating system need them.
+var isolatedEnv = []string{
+	"PATH",
+	"TMPDIR",
+	"TMP",
+	"TEMP",
+	"SYSTEMROOT",
+	"GOCOVERDIR",
+	"GORACE",
+	"GOTRACEBACK",
+}
+
+// IsolationSuite may be embedded in a suite to isolate each of its tests
+// from the environment of the process running them, and from each other.
+// For each test its SetUpTest:
+//
+//   - clears the environment, except for PATH, the temporary directory and
+//     the variables listed in KeepEnv;
+//   - sets HOME to a new directory made with MkDir;
+//   - changes the working directory to another new directory.
+//
+// All of these are undone once the test and its cleanups finish, and
+// os.Args is restored to what it was before SetUpTest. The test fails if,
+// by then, the environment or the working directory differ from those
+// SetUpTest left, as happens when the test changes them with os.Setenv or
+// os.Chdir rather than with its C's Setenv or Chdir.
+//
+// A suite with its own SetUpTest and TearDownTest must call those of the
+// IsolationSuite, or implement ChainedSuite to have the runner call them.
+// Tests of a ParallelSuite can't be isolated, as the environment and
+// working directory are shared by the whole process, so SetUpTest fails
+// them rather than let them race with each other. The changes are
+// undone when a retried test finishes, not after each attempt.
+type IsolationSuite struct {
+	// KeepEnv lists environment variables to keep in addition to the
+	// default ones.
+	KeepEnv []string
+}
+
+// SetUpTest isolates the test.
+func (s *IsolationSuite) SetUpTest(c *C) {
+	if c.runner != nil && c.runner.parallel {
+		c.fail("IsolationSuite can't isolate the tests of a ParallelSuite, " +
+			"as they share the environment and working directory of the process")
+		c.FailNow()
+	}
+	home, dir := c.MkDir(), c.MkDir()
+	env := environ()
+	wd, err := os.Getwd()
+	c.Assert(err, IsNil)
+	args := slices.Clone(os.Args)
+	var isolated map[string]string
+	// Registered after the directories are made, so that it runs before
+	// they are removed, and before anything else the test registers, so
+	// that it runs once the changes made by the test with Setenv or Chdir
+	// have been undone.
+	c.T.Cleanup(func() {
+		if isolated != nil {
+			checkIsolation(c, isolated, dir)
+		}
+		setEnviron(env)
+		if err := os.Chdir(wd); err != nil {
+			c.Errorf("cannot restore working directory: %v", err)
+		}
+		os.Args = args
+	})
+
+	for key := range env {
+		if !slices.Contains(isolatedEnv, key) && !slices.Contains(s.KeepEnv, key) {
+			os.Unsetenv(key)
+		}
+	}
+	os.Setenv("HOME", home)
+	c.Assert(os.Chdir(dir), IsNil)
+	isolated = environ()
+}
+
+// TearDownTest does nothing, as the isolation is undone by a cleanup of
+// the test, but is there for suites to call from their own TearDownTest.
+func (s *IsolationSuite) TearDownTest(c *C) {}
+
+// checkIsolation fails the test if the environment or the working
+// directory differ from the isolated ones it was given.
+func checkIsolation(c *C, env map[string]string, dir string) {
+	var changed []string
+	now := environ()
+	for key, value := range env {
+		if v, ok := now[key]; !ok || v != value {
+			changed = append(changed, key)
+		}
+	}
+	for key := range now {
+		if _, ok := env[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	if len(changed) > 0 {
+		sort.Strings(changed)
+		c.fail("test leaked changes to the environment: " + strings.Join(changed, ", "))
+	}
+	if wd, err := os.Getwd(); err != nil || wd != dir {
+		c.fail(fmt.Sprintf("test leaked a change of working directory to %q", wd))
+	}
+}
+
+// setEnviron replaces the process's environment variables with env.
+func setEnviron(env map[string]string) {
+	for key := range environ() {
+		if _, ok := env[key]; !ok {
+			os.Unsetenv(key)
+		}
+	}
+	for key, value := range env {
+		os.Setenv(key, value)
+	}
+}
+
+// environ returns the process's environment variables by name.
+func environ() map[string]string {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		env[key] = value
+	}
+	return env
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	leaksFlag = flag.Bool("tc.leaks", false, "Fail suite tests that leave goroutines running after TearDownTest")
+)
+
+// leakGracePeriod is how long goroutines started by a test are given to
+// finish after its TearDownTest, before they are reported as leaked.
+const leakGracePeriod = time.Second
+
+// LeakCheckSuite may be implemented by a suite to have each of its tests
+// checked for leaked goroutines, as the -tc.leaks flag does for all suites.
+// Goroutines whose stack contains any of the strings returned by
+// IgnoreLeaks, such as the name of a function, are not reported.
+//
+// Goroutines running when SetUpTest is called are never reported, nor are
+// those of the runtime and testing packages. Tests of a suite implementing
+// ParallelSuite are not checked, as they would see each other's goroutines.
+type LeakCheckSuite interface {
+	IgnoreLeaks() []string
+}
+
+// leakIgnores returns whether the suite's tests are to be checked for
+// leaked goroutines, and if so the stacks to ignore.
+func (runner *suiteRunner) leakIgnores() ([]string, bool) {
+	if runner.parallel {
+		return nil, false
+	}
+	if lc, ok := runner.suite.(LeakCheckSuite); ok {
+		return lc.IgnoreLeaks(), true
+	}
+	return nil, *leaksFlag
+}
+
+// checkLeaks fails the test if, within the grace period, there are still
+// goroutines running that weren't running before it.
+func checkLeaks(c *C, before map[int]bool, ignores []string) {
+	c.Helper()
+	deadline := time.Now().Add(leakGracePeriod)
+	delay := time.Millisecond
+	for {
+		leaked := leakedGoroutines(before, ignores)
+		if len(leaked) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			c.fail(fmt.Sprintf("%s leaked %d goroutine(s):\n\n%s",
+				c.TestName(), len(leaked), formatGoroutines(leaked)))
+			return
+		}
+		time.Sleep(delay)
+		delay = min(2*delay, 100*time.Millisecond)
+	}
+}
+
+// runningGoroutines returns the ids of the goroutines currently running.
+func runningGoroutines() map[int]bool {
+	ids := make(map[int]bool)
+	for _, g := range goroutines() {
+		ids[g.id] = true
+	}
+	return ids
+}
+
+// leakedGoroutines returns the goroutines that aren't in before, excluding
+// system goroutines and those matching any of the ignores.
+func leakedGoroutines(before map[int]bool, ignores []string) []goroutine {
+	var leaked []goroutine
+next:
+	for _, g := range goroutines() {
+		if before[g.id] || g.isSystem() {
+			continue
+		}
+		for _, ignore := range ignores {
+			if strings.Contains(g.stack, ignore) {
+				continue next
+			}
+		}
+		leaked = append(leaked, g)
+	}
+	return leaked
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+func init() {
+	flag.Var(&newListFlag, "tc.list", "List the names and tags of all tests that will be run, or with -tc.list=json their details")
+}
+
+// newListFlag holds the value of the -tc.list flag.
+var newListFlag listValue
+
+// listValue is a boolean flag.Value that also accepts the listing format.
+type listValue string
+
+const (
+	listOff  listValue = ""
+	listText listValue = "text"
+	listJSON listValue = "json"
+)
+
+func (v *listValue) IsBoolFlag() bool {
+	return true
+}
+
+func (v *listValue) String() string {
+	if *v == listOff {
+		return "false"
+	}
+	return string(*v)
+}
+
+func (v *listValue) Set(s string) error {
+	switch s {
+	case "false":
+		*v = listOff
+	case "true", "text":
+		*v = listText
+	case "json":
+		*v = listJSON
+	default:
+		return fmt.Errorf("want true, false, text or json, got %q", s)
+	}
+	return nil
+}
+
+// listedTest describes a test in a JSON listing.
+type listedTest struct {
+	Name         string   `json:"name"`
+	Suite        string   `json:"suite"`
+	Method       string   `json:"method"`
+	Package      string   `json:"package"`
+	File         string   `json:"file"`
+	Line         int      `json:"line"`
+	Fixtures     []string `json:"fixtures,omitempty"`
+	Requirements []string `json:"requirements,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+}
+
+// String returns the test's name followed by any tags, as in
+// "Suite.TestFoo [slow,db]", for the text listing.
+func (test listedTest) String() string {
+	if len(test.Tags) == 0 {
+		return test.Name
+	}
+	return test.Name + " [" + strings.Join(test.Tags, ",") + "]"
+}
+
+// listAll writes the tests of all registered suites that are in the
+// active shard and selected by their tags to w, in the given format.
+func listAll(w io.Writer, format listValue) error {
+	names, err := inShard(ListAll())
+	if err != nil {
+		return err
+	}
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		wanted[name] = true
+	}
+	tests := []listedTest{}
+	for _, suite := range shuffled(allSuites) {
+		runner := newSuiteRunner(suite)
+		for _, test := range runner.listTests() {
+			if wanted[test.Name] && runner.unselected(test.Method) == "" {
+				tests = append(tests, test)
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+	if format == listText {
+		for _, test := range tests {
+			fmt.Fprintln(bw, test)
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(tests, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = bw.Write(append(data, '\n'))
+	return err
+}
+
+// listTests returns a description of each of the suite's tests, in the
+// order List returns them.
+func (runner *suiteRunner) listTests() []listedTest {
+	var fixtures []string
+	for _, fixture := range []struct {
+		name  string
+		chain chain
+	}{
+		{"SetUpSuite", runner.setUpSuite},
+		{"TearDownSuite", runner.tearDownSuite},
+		{"SetUpTest", runner.setUpTest},
+		{"TearDownTest", runner.tearDownTest},
+		{"SetUpSubtest", runner.setUpSubtest},
+		{"TearDownSubtest", runner.tearDownSubtest},
+	} {
+		for _, method := range fixture.chain {
+			if method != nil {
+				fixtures = append(fixtures, fixture.name)
+				break
+			}
+		}
+	}
+	var suiteRequirements []string
+	for _, req := range runner.requirements("") {
+		suiteRequirements = append(suiteRequirements, req.String())
+	}
+
+	var tests []listedTest
+	for _, method := range runner.tests {
+		frame, _ := runtime.CallersFrames([]uintptr{method.PC()}).Next()
+		test := listedTest{
+			Name:         method.String(),
+			Suite:        suiteName(runner.suite),
+			Method:       method.Info.Name,
+			Package:      suiteType(runner.suite).PkgPath(),
+			File:         frame.File,
+			Line:         frame.Line,
+			Fixtures:     fixtures,
+			Requirements: append([]string(nil), suiteRequirements...),
+			Tags:         runner.tags(method.Info.Name),
+		}
+		for _, req := range runner.requirements(method.Info.Name) {
+			test.Requirements = append(test.Requirements, req.String())
+		}
+		tests = append(tests, test)
+	}
+	return tests
+}
+
+// suiteType returns the type the suite is named after, without any
+// pointer.
+func suiteType(suite any) reflect.Type {
+	t := reflect.TypeOf(suite)
+	if named, ok := suite.(namedSuite); ok {
+		t = named.namedType()
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// inTestPhase is inPhase for the fixtures and body of a test, turning a
+// panic in f into a failure of the test. A panic stops the test where a
+// failed assertion would, except in TearDownTest, where the test is only
+// marked as failed so that the rest of the teardown still runs.
+func (c *C) inTestPhase(phase string, f func()) {
+	c.Helper()
+	c.inPhase(phase, func() {
+		defer c.recoverPanic(phase != phaseTearDownTest)
+		f()
+	})
+}
+
+// recoverPanic, when deferred, recovers a panic and reports it as a
+// failure of the test, stopping the test if stop is set.
+func (c *C) recoverPanic(stop bool) {
+	value := recover()
+	if value == nil {
+		return
+	}
+	c.fail(formatPanic(value, panicStack()))
+	if stop {
+		c.FailNow()
+	}
+}
+
+// panicStack returns the frames of a panicking goroutine, called from a
+// deferred function, from the function that panicked up to the runner.
+func panicStack() []runtime.Frame {
+	pcs := make([]uintptr, 64)
+	pcs = pcs[:runtime.Callers(3, pcs)]
+	frames := runtime.CallersFrames(pcs)
+	var stack []runtime.Frame
+	seenUser := false
+	for {
+		frame, more := frames.Next()
+		pkg := funcPackage(frame.Function)
+		switch {
+		case pkg == "runtime" && len(stack) == 0:
+			// Frames of the panic itself.
+		case pkg == "reflect" || pkg == "testing" || pkg == "runtime":
+			return stack
+		case pkg == "github.com/juju/tc" && seenUser:
+			// Back in the runner.
+			return stack
+		default:
+			seenUser = seenUser || pkg != "github.com/juju/tc"
+			stack = append(stack, frame)
+		}
+		if !more {
+			return stack
+		}
+	}
+}
+
+// formatPanic returns a description of a panic with the given value and
+// stack, in the style of a failed check: the line that panicked, the panic
+// value, and then the stack.
+func formatPanic(value any, stack []runtime.Frame) string {
+	var lines []string
+	// Point at the suite's code, rather than any of this package's that
+	// it called.
+	for _, frame := range stack {
+		if funcPackage(frame.Function) == "github.com/juju/tc" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s:%d", frame.File, frame.Line))
+		if code, _ := printLine(frame.File, frame.Line); code != "" {
+			lines = append(lines, indent(code, "    "))
+		}
+		break
+	}
+	lines = append(lines, fmt.Sprintf("... Panic: %v", value), "")
+	for _, frame := range stack {
+		name := frame.Function
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			name = name[i+1:]
+		}
+		lines = append(lines, fmt.Sprintf("%s:%d", frame.File, frame.Line), "  in "+name)
+	}
+	return strings.Join(lines, "\n")
+}
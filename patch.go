@@ -0,0 +1,112 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// PatchValue sets the value pointed to by dest to value, and restores the
+// original value when the test finishes. A nil value sets it to its zero
+// value. PatchValue panics if dest isn't a non-nil pointer, or if value
+// can't be assigned to what it points to.
+func PatchValue(t LikeTB, dest, value any) {
+	t.Helper()
+	destv := reflect.ValueOf(dest)
+	if destv.Kind() != reflect.Ptr || destv.IsNil() {
+		panic(fmt.Sprintf("PatchValue: destination must be a non-nil pointer, not %T", dest))
+	}
+	elem := destv.Elem()
+	var newv reflect.Value
+	if value == nil {
+		newv = reflect.Zero(elem.Type())
+	} else {
+		newv = reflect.ValueOf(value)
+		if !newv.Type().AssignableTo(elem.Type()) {
+			panic(fmt.Sprintf("PatchValue: cannot assign %T to %s", value, elem.Type()))
+		}
+	}
+	oldv := reflect.New(elem.Type()).Elem()
+	oldv.Set(elem)
+	elem.Set(newv)
+	t.Cleanup(func() {
+		elem.Set(oldv)
+	})
+}
+
+// PatchEnvironment sets the environment variable name to value, and
+// restores its original value, or unsets it if it wasn't set, when the
+// test finishes. Unlike Setenv, it may be used by parallel tests, which
+// then see each other's changes.
+func PatchEnvironment(t LikeTB, name, value string) {
+	t.Helper()
+	old, set := os.LookupEnv(name)
+	os.Setenv(name, value)
+	t.Cleanup(func() {
+		restoreEnv(name, old, set)
+	})
+}
+
+// restoreEnv sets the environment variable name back to old if it was
+// set, or unsets it.
+func restoreEnv(name, old string, set bool) {
+	if set {
+		os.Setenv(name, old)
+	} else {
+		os.Unsetenv(name)
+	}
+}
+
+// PatchEnvPathPrepend adds dir to the front of the PATH environment
+// variable, and restores it when the test finishes.
+func PatchEnvPathPrepend(t LikeTB, dir string) {
+	t.Helper()
+	path := dir
+	if old := os.Getenv("PATH"); old != "" {
+		path += string(os.PathListSeparator) + old
+	}
+	PatchEnvironment(t, "PATH", path)
+}
+
+// PatchValue is equivalent to the PatchValue function called with c.
+func (c *C) PatchValue(dest, value any) {
+	c.Helper()
+	PatchValue(c, dest, value)
+}
+
+// PatchEnvironment is equivalent to the PatchEnvironment function called
+// with c.
+func (c *C) PatchEnvironment(name, value string) {
+	c.Helper()
+	PatchEnvironment(c, name, value)
+}
+
+// PatchEnvPathPrepend is equivalent to the PatchEnvPathPrepend function
+// called with c.
+func (c *C) PatchEnvPathPrepend(dir string) {
+	c.Helper()
+	PatchEnvPathPrepend(c, dir)
+}
+
+// PatchValue is equivalent to the PatchValue function called with tbc.
+func (tbc *TBC) PatchValue(dest, value any) {
+	tbc.Helper()
+	PatchValue(tbc, dest, value)
+}
+
+// PatchEnvironment is equivalent to the PatchEnvironment function called
+// with tbc.
+func (tbc *TBC) PatchEnvironment(name, value string) {
+	tbc.Helper()
+	PatchEnvironment(tbc, name, value)
+}
+
+// PatchEnvPathPrepend is equivalent to the PatchEnvPathPrepend function
+// called with tbc.
+func (tbc *TBC) PatchEnvPathPrepend(dir string) {
+	tbc.Helper()
+	PatchEnvPathPrepend(tbc, dir)
+}
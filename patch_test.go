@@ -0,0 +1,89 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/juju/tc"
+)
+
+type PatchS struct{}
+
+var _ = tc.InternalSuite(&PatchS{})
+
+var (
+	patchedInt   = 1
+	patchedError = errors.New("original")
+	patchedSlice = []string{"original"}
+)
+
+func (s *PatchS) TestPatchValue(c *tc.C) {
+	c.T.Run("patched", func(t *testing.T) {
+		pc := &tc.C{T: t}
+		pc.PatchValue(&patchedInt, 2)
+		pc.PatchValue(&patchedError, errors.New("patched"))
+		pc.PatchValue(&patchedSlice, nil)
+		c.Check(patchedInt, tc.Equals, 2)
+		c.Check(patchedError, tc.ErrorMatches, "patched")
+		c.Check(patchedSlice, tc.IsNil)
+
+		// Patching twice restores in order.
+		pc.PatchValue(&patchedInt, 3)
+		c.Check(patchedInt, tc.Equals, 3)
+	})
+	c.Check(patchedInt, tc.Equals, 1)
+	c.Check(patchedError, tc.ErrorMatches, "original")
+	c.Check(patchedSlice, tc.DeepEquals, []string{"original"})
+}
+
+func (s *PatchS) TestPatchValueTBC(c *tc.C) {
+	c.T.Run("patched", func(t *testing.T) {
+		tbc := &tc.TBC{TB: t}
+		tbc.PatchValue(&patchedInt, 2)
+		c.Check(patchedInt, tc.Equals, 2)
+	})
+	c.Check(patchedInt, tc.Equals, 1)
+}
+
+func (s *PatchS) TestPatchValuePanics(c *tc.C) {
+	c.Check(func() { c.PatchValue(patchedInt, 2) }, tc.PanicMatches,
+		`PatchValue: destination must be a non-nil pointer, not int`)
+	c.Check(func() { c.PatchValue((*int)(nil), 2) }, tc.PanicMatches,
+		`PatchValue: destination must be a non-nil pointer, not \*int`)
+	c.Check(func() { c.PatchValue(&patchedInt, "2") }, tc.PanicMatches,
+		`PatchValue: cannot assign string to int`)
+	c.Check(func() { tc.PatchValue(c, &patchedError, 2) }, tc.PanicMatches,
+		`PatchValue: cannot assign int to error`)
+	c.Check(patchedInt, tc.Equals, 1)
+}
+
+func (s *PatchS) TestPatchEnvironment(c *tc.C) {
+	c.Setenv("TC_PATCH_SET", "original")
+	os.Unsetenv("TC_PATCH_UNSET")
+	c.T.Run("patched", func(t *testing.T) {
+		pc := &tc.C{T: t}
+		pc.PatchEnvironment("TC_PATCH_SET", "patched")
+		tc.PatchEnvironment(pc, "TC_PATCH_UNSET", "patched")
+		c.Check(os.Getenv("TC_PATCH_SET"), tc.Equals, "patched")
+		c.Check(os.Getenv("TC_PATCH_UNSET"), tc.Equals, "patched")
+	})
+	c.Check(os.Getenv("TC_PATCH_SET"), tc.Equals, "original")
+	_, set := os.LookupEnv("TC_PATCH_UNSET")
+	c.Check(set, tc.IsFalse)
+}
+
+func (s *PatchS) TestPatchEnvPathPrepend(c *tc.C) {
+	path := os.Getenv("PATH")
+	dir := c.MkDir()
+	c.T.Run("patched", func(t *testing.T) {
+		tbc := &tc.TBC{TB: t}
+		tbc.PatchEnvPathPrepend(dir)
+		c.Check(os.Getenv("PATH"), tc.Equals, dir+string(filepath.ListSeparator)+path)
+	})
+	c.Check(os.Getenv("PATH"), tc.Equals, path)
+}
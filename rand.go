@@ -0,0 +1,61 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	seedFlag = flag.Int64("tc.seed", 0, "Seed the random numbers returned by C.Rand from this, to replay a run; by default a seed is picked for each run")
+)
+
+// runSeed returns the seed every test's random numbers are derived from,
+// picked once for the run unless -tc.seed gives it.
+var runSeed = sync.OnceValue(func() int64 {
+	if *seedFlag != 0 {
+		return *seedFlag
+	}
+	return time.Now().UnixNano()
+})
+
+// Rand returns a source of random numbers for the test, seeded from the
+// seed of the run and the test's name, so that the test gets the same
+// numbers whenever it is run with the same -tc.seed, whether or not other
+// tests run too. If the test fails after calling Rand, the seed is printed
+// so that the failure can be replayed. Each attempt of a retried test
+// starts again from the seed, while each iteration of a stressed test gets
+// its own numbers.
+//
+// The returned *rand.Rand is not safe for use by multiple goroutines.
+func (c *C) Rand() *rand.Rand {
+	c.mu.Lock()
+	r := c.rand
+	c.mu.Unlock()
+	if r != nil {
+		return r
+	}
+
+	seed := runSeed()
+	h := fnv.New64a()
+	h.Write([]byte(c.Name()))
+	if c.iteration > 0 {
+		fmt.Fprintf(h, "#%d", c.iteration)
+	}
+	r = rand.New(rand.NewSource(seed ^ int64(h.Sum64())))
+	c.mu.Lock()
+	c.rand = r
+	c.mu.Unlock()
+	c.Cleanup(func() {
+		if c.Failed() {
+			fmt.Fprintf(c.Output(), "c.Rand was seeded with -tc.seed=%d\n", seed)
+		}
+	})
+	return r
+}
@@ -0,0 +1,355 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	reportFlag = flag.String("tc.report", "", "Write suite results to a report, as junit:path or json:path, or a comma separated list of these")
+)
+
+var (
+	// reportMu guards reportedSuites.
+	reportMu sync.Mutex
+	// reportedSuites holds the results of every suite run so far, so
+	// that each report written includes all of them.
+	reportedSuites []*suiteReport
+)
+
+// suiteReport holds the results of running a suite.
+type suiteReport struct {
+	name       string
+	pkg        string
+	prefix     string
+	duration   time.Duration
+	timings    map[string]time.Duration
+	skipReason string
+	failures   []string
+	// slowest is whether to print the slowest report for the suite
+	// once it finishes.
+	slowest bool
+
+	mu    sync.Mutex
+	tests []*testReport
+}
+
+// testReport holds the result of a single suite test.
+type testReport struct {
+	name       string
+	status     string
+	duration   time.Duration
+	timings    map[string]time.Duration
+	skipReason string
+	failures   []string
+	tags       []string
+	// attempts is how many times a retried test was run, or zero if it
+	// wasn't retried.
+	attempts int
+}
+
+// The statuses of a test in reports.
+const (
+	statusPass = "pass"
+	statusFail = "fail"
+	statusSkip = "skip"
+)
+
+// reporting reports whether suite results need to be collected.
+func reporting() bool {
+	return *reportFlag != "" || *slowestFlag > 0
+}
+
+func newSuiteReport(t *testing.T, suite any) *suiteReport {
+	return &suiteReport{
+		name:   suiteName(suite),
+		pkg:    suiteType(suite).PkgPath(),
+		prefix: t.Name() + "/",
+	}
+}
+
+// addTest records the result of the test run with c, which has the given
+// tags.
+func (r *suiteReport) addTest(c *C, duration time.Duration, tags []string) {
+	result := &testReport{
+		name:     strings.TrimPrefix(c.Name(), r.prefix),
+		status:   testStatus(c.T),
+		duration: duration,
+		tags:     tags,
+	}
+	c.mu.Lock()
+	if c.status != "" && result.status == statusPass {
+		// Only a test that still passed can be flaky or quarantined,
+		// as it may have failed after its last attempt.
+		result.status = c.status
+	}
+	if c.attempts > 1 {
+		result.attempts = c.attempts
+	}
+	result.timings = c.timings
+	result.skipReason = c.reason
+	result.failures = c.failures
+	c.mu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tests = append(r.tests, result)
+}
+
+// finish records the suite level results from c, and writes the reports
+// asked for including this suite.
+func (r *suiteReport) finish(c *C, duration time.Duration) {
+	r.duration = duration
+	c.mu.Lock()
+	r.timings = c.timings
+	r.skipReason = c.reason
+	r.failures = c.failures
+	c.mu.Unlock()
+
+	reportMu.Lock()
+	defer reportMu.Unlock()
+	reportedSuites = append(reportedSuites, r)
+	if r.slowest && *slowestFlag > 0 {
+		printSlowest(os.Stdout, *slowestFlag, []*suiteReport{r})
+	}
+	if *reportFlag == "" {
+		return
+	}
+	if err := writeReports(*reportFlag, reportedSuites); err != nil {
+		c.Errorf("cannot write report: %v", err)
+	}
+}
+
+func testStatus(t *testing.T) string {
+	switch {
+	case t.Failed():
+		return statusFail
+	case t.Skipped():
+		return statusSkip
+	}
+	return statusPass
+}
+
+// writeReports writes the given suite results to each of the reports in
+// spec, a comma separated list of format:path pairs.
+func writeReports(spec string, suites []*suiteReport) error {
+	for _, report := range strings.Split(spec, ",") {
+		format, path, ok := strings.Cut(report, ":")
+		if !ok || path == "" {
+			return fmt.Errorf("report %q is not of the form format:path", report)
+		}
+		var data []byte
+		var err error
+		switch format {
+		case "junit":
+			data, err = junitReport(suites)
+		case "json":
+			data, err = jsonReport(suites)
+		default:
+			return fmt.Errorf("unknown report format %q", format)
+		}
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seconds returns the duration in seconds, as reports give it.
+func seconds(d time.Duration) float64 {
+	return d.Seconds()
+}
+
+func timingSeconds(timings map[string]time.Duration) map[string]float64 {
+	if len(timings) == 0 {
+		return nil
+	}
+	result := make(map[string]float64, len(timings))
+	for phase, d := range timings {
+		result[phase] = seconds(d)
+	}
+	return result
+}
+
+type jsonSuite struct {
+	Name       string             `json:"name"`
+	Package    string             `json:"package"`
+	Duration   float64            `json:"duration"`
+	Fixtures   map[string]float64 `json:"fixtures,omitempty"`
+	SkipReason string             `json:"skip_reason,omitempty"`
+	Failures   []string           `json:"failures,omitempty"`
+	Tests      []jsonTest         `json:"tests"`
+}
+
+type jsonTest struct {
+	Name       string             `json:"name"`
+	Status     string             `json:"status"`
+	Duration   float64            `json:"duration"`
+	Fixtures   map[string]float64 `json:"fixtures,omitempty"`
+	SkipReason string             `json:"skip_reason,omitempty"`
+	Failures   []string           `json:"failures,omitempty"`
+	Tags       []string           `json:"tags,omitempty"`
+	Attempts   int                `json:"attempts,omitempty"`
+}
+
+func jsonReport(suites []*suiteReport) ([]byte, error) {
+	result := make([]jsonSuite, 0, len(suites))
+	for _, suite := range suites {
+		js := jsonSuite{
+			Name:       suite.name,
+			Package:    suite.pkg,
+			Duration:   seconds(suite.duration),
+			Fixtures:   timingSeconds(suite.timings),
+			SkipReason: suite.skipReason,
+			Failures:   suite.failures,
+			Tests:      []jsonTest{},
+		}
+		for _, test := range suite.sortedTests() {
+			js.Tests = append(js.Tests, jsonTest{
+				Name:       test.name,
+				Status:     test.status,
+				Duration:   seconds(test.duration),
+				Fixtures:   timingSeconds(test.timings),
+				SkipReason: test.skipReason,
+				Failures:   test.failures,
+				Tags:       test.tags,
+				Attempts:   test.attempts,
+			})
+		}
+		result = append(result, js)
+	}
+	return json.MarshalIndent(result, "", "  ")
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Package    string          `xml:"package,attr,omitempty"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	TestCases  []junitTestCase `xml:"testcase"`
+	SystemErr  string          `xml:"system-err,omitempty"`
+}
+
+type junitTestCase struct {
+	ClassName  string          `xml:"classname,attr"`
+	Name       string          `xml:"name,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Failure    *junitMessage   `xml:"failure,omitempty"`
+	Skipped    *junitMessage   `xml:"skipped,omitempty"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", seconds(d))
+}
+
+// junitTimings returns fixture timings as JUnit properties, in a stable
+// order.
+func junitTimings(timings map[string]time.Duration) []junitProperty {
+	var properties []junitProperty
+	for phase, d := range timings {
+		properties = append(properties, junitProperty{Name: phase, Value: junitTime(d)})
+	}
+	sort.Slice(properties, func(i, j int) bool {
+		return properties[i].Name < properties[j].Name
+	})
+	return properties
+}
+
+func junitReport(suites []*suiteReport) ([]byte, error) {
+	var result junitTestSuites
+	for _, suite := range suites {
+		js := junitTestSuite{
+			Name:       suite.name,
+			Package:    suite.pkg,
+			Time:       junitTime(suite.duration),
+			Properties: junitTimings(suite.timings),
+			SystemErr:  strings.Join(suite.failures, "\n"),
+		}
+		for _, test := range suite.sortedTests() {
+			tc := junitTestCase{
+				ClassName:  strings.TrimPrefix(suite.pkg+"."+suite.name, "."),
+				Name:       test.name,
+				Time:       junitTime(test.duration),
+				Properties: junitTimings(test.timings),
+			}
+			if len(test.tags) > 0 {
+				tc.Properties = append(tc.Properties, junitProperty{Name: "tags", Value: strings.Join(test.tags, ",")})
+			}
+			if test.attempts > 0 {
+				tc.Properties = append(tc.Properties, junitProperty{Name: "attempts", Value: fmt.Sprint(test.attempts)})
+			}
+			js.Tests++
+			switch test.status {
+			case statusFail:
+				js.Failures++
+				tc.Failure = &junitMessage{
+					Message: "failed",
+					Text:    strings.Join(test.failures, "\n"),
+				}
+			case statusSkip:
+				js.Skipped++
+				tc.Skipped = &junitMessage{Message: test.skipReason}
+			case statusQuarantined:
+				// Reported as skipped, so as not to fail the build,
+				// with the failures kept.
+				js.Skipped++
+				tc.Skipped = &junitMessage{
+					Message: "quarantined",
+					Text:    strings.Join(test.failures, "\n"),
+				}
+			}
+			js.TestCases = append(js.TestCases, tc)
+		}
+		result.Suites = append(result.Suites, js)
+	}
+	data, err := xml.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// sortedTests returns the suite's test results in name order, as parallel
+// tests may finish in any order.
+func (r *suiteReport) sortedTests() []*testReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tests := append([]*testReport(nil), r.tests...)
+	sort.SliceStable(tests, func(i, j int) bool {
+		return tests[i].name < tests[j].name
+	})
+	return tests
+}
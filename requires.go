@@ -0,0 +1,134 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+var (
+	forceRunFlag = flag.Bool("tc.force-run", false, "Run suites and tests even if their requirements are not met")
+)
+
+// Requirement is a condition that must hold for a suite or test to be
+// run. See RequirementsSuite.
+type Requirement interface {
+	// String describes the requirement.
+	String() string
+	// Check returns an error saying why the requirement isn't met, or
+	// nil if it is.
+	Check() error
+}
+
+// RequirementsSuite may be implemented by a suite to have the runner skip
+// the whole suite, or some of its test methods, when their requirements
+// aren't met. Requirements returns the requirements of the named test
+// method, or of the suite as a whole when method is "". They are checked
+// before any fixture is run, and the skip reports those that aren't met.
+//
+// The -tc.force-run flag runs everything regardless, to find tests that
+// have stopped working where they are usually skipped.
+type RequirementsSuite interface {
+	Requirements(method string) []Requirement
+}
+
+type requirement struct {
+	desc  string
+	check func() error
+}
+
+func (r requirement) String() string {
+	return r.desc
+}
+
+func (r requirement) Check() error {
+	return r.check()
+}
+
+// Require returns a Requirement with the given description that is met
+// when check returns nil.
+func Require(desc string, check func() error) Requirement {
+	return requirement{desc: desc, check: check}
+}
+
+// RequireRoot returns a Requirement that the test runs as root.
+func RequireRoot() Requirement {
+	return Require("root", func() error {
+		if uid := os.Geteuid(); uid != 0 {
+			return fmt.Errorf("running as uid %d, not root", uid)
+		}
+		return nil
+	})
+}
+
+// RequireBinary returns a Requirement that the named binary is found on
+// $PATH.
+func RequireBinary(name string) Requirement {
+	return Require("binary "+name, func() error {
+		if _, err := exec.LookPath(name); err != nil {
+			return fmt.Errorf("%s not found on $PATH", name)
+		}
+		return nil
+	})
+}
+
+// RequireEnv returns a Requirement that the named environment variable is
+// set to a non-empty value.
+func RequireEnv(name string) Requirement {
+	return Require("$"+name, func() error {
+		if os.Getenv(name) == "" {
+			return fmt.Errorf("$%s is not set", name)
+		}
+		return nil
+	})
+}
+
+// RequireOS returns a Requirement that the test runs on one of the given
+// operating systems, as named by runtime.GOOS.
+func RequireOS(goos ...string) Requirement {
+	return Require("GOOS "+strings.Join(goos, " or "), func() error {
+		if !slices.Contains(goos, runtime.GOOS) {
+			return fmt.Errorf("running on %s, not %s", runtime.GOOS, strings.Join(goos, " or "))
+		}
+		return nil
+	})
+}
+
+// requirements returns the requirements of the named test method, or of
+// the suite if method is "".
+func (runner *suiteRunner) requirements(method string) []Requirement {
+	if rs, ok := runner.suite.(RequirementsSuite); ok {
+		return rs.Requirements(method)
+	}
+	return nil
+}
+
+// unmetRequirements returns the reason to skip the named test method, or
+// the suite if method is "", or "" if all its requirements are met. If
+// the -tc.force-run flag is set, the unmet requirements are written to w
+// and "" is returned.
+func (runner *suiteRunner) unmetRequirements(w io.Writer, method string) string {
+	var unmet []string
+	for _, req := range runner.requirements(method) {
+		if err := req.Check(); err != nil {
+			unmet = append(unmet, err.Error())
+		}
+	}
+	if len(unmet) == 0 {
+		return ""
+	}
+	reason := "unmet requirements: " + strings.Join(unmet, "; ")
+	if *forceRunFlag {
+		fmt.Fprintf(w, "running with -tc.force-run despite %s\n", reason)
+		return ""
+	}
+	return reason
+}
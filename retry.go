@@ -0,0 +1,376 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+var (
+	retriesFlag = flag.Int("tc.retries", 0, "Run failed suite tests again up to this many times, passing them if any attempt passes")
+)
+
+// The tags that opt a test in to being retried or quarantined, as an
+// alternative to implementing RetrySuite or QuarantineSuite.
+const (
+	flakyTag      = "flaky"
+	quarantineTag = "quarantine"
+)
+
+// flakyRetries is how many times a test tagged flaky is run again after
+// failing, unless -tc.retries gives more.
+const flakyRetries = 2
+
+// The statuses of a retried or quarantined test in reports, besides those
+// of any other test.
+const (
+	// statusFlaky is the status of a test that passed after failing.
+	statusFlaky = "flaky"
+	// statusQuarantined is the status of a quarantined test that failed
+	// every attempt, without failing the run.
+	statusQuarantined = "quarantined"
+)
+
+// RetrySuite may be implemented by a suite to have its failed test methods
+// run again, each attempt with its own SetUpTest and TearDownTest, up to
+// the returned number of times. A test passes if any attempt passes, and
+// is reported as flaky if it needed more than one. A positive number
+// overrides the -tc.retries flag, zero defers to it, and a negative number
+// means the method is never retried. Tests tagged "flaky" are retried
+// twice, or as many times as -tc.retries gives if that is more.
+//
+// Cleanups registered with the test's C, including those of its Setenv
+// and Chdir, are run at the end of each attempt. The output of every
+// attempt is printed once the test finishes. Only
+// test methods taking a *C are retried, as failures reported through a
+// *testing.T, including those of subtests, cannot be taken back.
+type RetrySuite interface {
+	RetriesFor(method string) int
+}
+
+// QuarantineSuite may be implemented by a suite to quarantine some of its
+// test methods. A quarantined test is run, and retried like any other,
+// but if it fails it is reported as quarantined rather than failing the
+// run. Tests tagged "quarantine" are also quarantined.
+type QuarantineSuite interface {
+	Quarantined(method string) bool
+}
+
+// retryPolicy returns how many times the given test method is run again
+// if it fails, and whether it is quarantined.
+func (runner *suiteRunner) retryPolicy(method *methodType) (retries int, quarantined bool) {
+	if method.Info.Type.In(firstArg(method.Info.Type)) == testingT {
+		return 0, false
+	}
+	tags := runner.tags(method.Info.Name)
+	retries = *retriesFlag
+	if slices.Contains(tags, flakyTag) {
+		retries = max(retries, flakyRetries)
+	}
+	if rs, ok := runner.suite.(RetrySuite); ok {
+		if n := rs.RetriesFor(method.Info.Name); n != 0 {
+			retries = n
+		}
+	}
+	quarantined = slices.Contains(tags, quarantineTag)
+	if qs, ok := runner.suite.(QuarantineSuite); ok && qs.Quarantined(method.Info.Name) {
+		quarantined = true
+	}
+	return max(retries, 0), quarantined
+}
+
+// attempt holds the outcome of one run of a retried or quarantined test,
+// which is kept from the test's *testing.T until every attempt is done.
+type attempt struct {
+	output   bytes.Buffer
+	failed   bool
+	skipped  bool
+	cleanups []func()
+}
+
+// runRetried calls run up to retries+1 times, until an attempt doesn't
+// fail, and then prints the output of every attempt and passes the
+// outcome of the last one on to the test, unless it is a quarantined
+// failure.
+func (c *C) runRetried(retries int, quarantined bool, run func()) {
+	var attempts []*attempt
+	for range retries + 1 {
+		a := &attempt{}
+		c.runAttempt(a, run)
+		attempts = append(attempts, a)
+		if !a.failed || a.skipped {
+			break
+		}
+	}
+
+	w := c.T.Output()
+	for i, a := range attempts {
+		if len(attempts) == 1 {
+			w.Write(a.output.Bytes())
+			break
+		}
+		fmt.Fprintf(w, "attempt %d of %d: %s\n", i+1, retries+1, a.outcome())
+		w.Write([]byte(indent(a.output.String(), "    ")))
+	}
+
+	last := attempts[len(attempts)-1]
+	c.mu.Lock()
+	c.attempts = len(attempts)
+	c.mu.Unlock()
+	switch {
+	case last.skipped:
+		c.T.SkipNow()
+	case last.failed && quarantined:
+		c.setStatus(statusQuarantined)
+		fmt.Fprintln(w, "quarantined test failed, not failing the run")
+	case last.failed:
+		c.T.Fail()
+	case len(attempts) > 1:
+		c.setStatus(statusFlaky)
+		fmt.Fprintf(w, "flaky test passed on attempt %d of %d\n", len(attempts), retries+1)
+	}
+}
+
+// runAttempt calls run in a new goroutine, so that FailNow and SkipNow
+// end only this attempt, with the test's failures, skips, output and
+// cleanups kept in a.
+func (c *C) runAttempt(a *attempt, run func()) {
+	c.mu.Lock()
+	c.attempt = a
+	c.rand = nil
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.attempt = nil
+		c.mu.Unlock()
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer a.cleanup(c)
+		run()
+	}()
+	<-done
+}
+
+// cleanup calls the attempt's cleanups in the reverse of the order they
+// were registered, as testing.T does.
+func (a *attempt) cleanup(c *C) {
+	for {
+		c.mu.Lock()
+		if len(a.cleanups) == 0 {
+			c.mu.Unlock()
+			return
+		}
+		f := a.cleanups[len(a.cleanups)-1]
+		a.cleanups = a.cleanups[:len(a.cleanups)-1]
+		c.mu.Unlock()
+		f()
+	}
+}
+
+func (a *attempt) outcome() string {
+	switch {
+	case a.failed:
+		return "FAIL"
+	case a.skipped:
+		return "SKIP"
+	}
+	return "PASS"
+}
+
+// current returns the attempt being run, or nil if the test isn't being
+// retried. Once the runner has given up waiting for the test, it returns
+// a failed attempt that is thrown away, so that the abandoned goroutine
+// can't report to a test that has finished.
+func (c *C) current() *attempt {
+	if c.abandoned.Load() {
+		return &attempt{failed: true}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.attempt
+}
+
+func (c *C) setStatus(status string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status = status
+}
+
+// attemptWriter writes to the output of the test's current attempt.
+type attemptWriter struct {
+	c *C
+	a *attempt
+}
+
+func (w attemptWriter) Write(p []byte) (int, error) {
+	w.c.mu.Lock()
+	defer w.c.mu.Unlock()
+	return w.a.output.Write(p)
+}
+
+// logAttempt writes s to the output of attempt a, after the file and line
+// of the code that called into this package, as testing.T does.
+func (c *C) logAttempt(a *attempt, s string) {
+	line := "???:1"
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if funcPackage(frame.Function) != "github.com/juju/tc" {
+			line = fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+			break
+		}
+		if !more {
+			break
+		}
+	}
+	fmt.Fprintf(attemptWriter{c, a}, "%s: %s\n", line, strings.TrimSuffix(s, "\n"))
+}
+
+// -----------------------------------------------------------------------
+// Methods of testing.T kept by an attempt, rather than passed on to the
+// test, while a retried test runs.
+
+// Output returns a writer for output printed directly by the test.
+func (c *C) Output() io.Writer {
+	if a := c.current(); a != nil {
+		return attemptWriter{c, a}
+	}
+	return c.T.Output()
+}
+
+// Log formats its arguments as fmt.Println does and records the text in
+// the test's log.
+func (c *C) Log(args ...any) {
+	c.Helper()
+	if a := c.current(); a != nil {
+		c.logAttempt(a, fmt.Sprintln(args...))
+		return
+	}
+	c.T.Log(args...)
+}
+
+// Logf formats its arguments as fmt.Printf does and records the text in
+// the test's log.
+func (c *C) Logf(format string, args ...any) {
+	c.Helper()
+	if a := c.current(); a != nil {
+		c.logAttempt(a, fmt.Sprintf(format, args...))
+		return
+	}
+	c.T.Logf(format, args...)
+}
+
+// Fail marks the test as having failed but continues its execution.
+func (c *C) Fail() {
+	if a := c.current(); a != nil {
+		c.mu.Lock()
+		a.failed = true
+		c.mu.Unlock()
+		return
+	}
+	c.T.Fail()
+}
+
+// FailNow marks the test as having failed and stops its execution.
+func (c *C) FailNow() {
+	if c.current() != nil {
+		c.Fail()
+		runtime.Goexit()
+	}
+	c.T.FailNow()
+}
+
+// Failed reports whether the test has failed.
+func (c *C) Failed() bool {
+	if a := c.current(); a != nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return a.failed
+	}
+	return c.T.Failed()
+}
+
+// SkipNow marks the test as having been skipped and stops its execution.
+func (c *C) SkipNow() {
+	if a := c.current(); a != nil {
+		c.mu.Lock()
+		a.skipped = true
+		c.mu.Unlock()
+		runtime.Goexit()
+	}
+	c.T.SkipNow()
+}
+
+// Skipped reports whether the test was skipped.
+func (c *C) Skipped() bool {
+	if a := c.current(); a != nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return a.skipped
+	}
+	return c.T.Skipped()
+}
+
+// Cleanup registers a function to be called when the test and all its
+// subtests complete, or when the current attempt of a retried test does.
+func (c *C) Cleanup(f func()) {
+	if a := c.current(); a != nil {
+		c.mu.Lock()
+		a.cleanups = append(a.cleanups, f)
+		c.mu.Unlock()
+		return
+	}
+	c.T.Cleanup(f)
+}
+
+// Setenv calls os.Setenv and restores the variable when the test finishes,
+// as testing.T does, and also when the current attempt of a retried test
+// does, so that every attempt starts with the same environment.
+func (c *C) Setenv(key, value string) {
+	c.Helper()
+	if c.current() == nil {
+		c.T.Setenv(key, value)
+		return
+	}
+	old, set := os.LookupEnv(key)
+	c.T.Setenv(key, value)
+	c.Cleanup(func() {
+		restoreEnv(key, old, set)
+	})
+}
+
+// Chdir calls os.Chdir and restores the working directory when the test
+// finishes, as testing.T does, and also when the current attempt of a
+// retried test does.
+func (c *C) Chdir(dir string) {
+	c.Helper()
+	if c.current() == nil {
+		c.T.Chdir(dir)
+		return
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		c.Fatal(err)
+	}
+	pwd, set := os.LookupEnv("PWD")
+	c.T.Chdir(dir)
+	c.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			panic("tc: cannot restore working directory: " + err.Error())
+		}
+		restoreEnv("PWD", pwd, set)
+	})
+}
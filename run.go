@@ -27,8 +27,6 @@
 package tc
 
 import (
-	"bufio"
-	"flag"
 	"fmt"
 	"os"
 	"testing"
@@ -48,42 +46,95 @@ func InternalSuite(suite any) any {
 	return suite
 }
 
+// ParallelSuite may be implemented by a suite to have its test methods run
+// in parallel with each other. Each test runs against its own shallow copy
+// of the suite value, taken after SetUpSuite, so any state set by SetUpTest
+// is private to that test. Pointers, maps and slices held by the suite are
+// still shared between the copies.
+type ParallelSuite interface {
+	Parallel() bool
+}
+
+// ChainedSuite may be implemented by a suite to have the fixture methods of
+// every suite embedded in it called, not only those reached through Go
+// method promotion. The SetUpSuite and SetUpTest methods of each layer are
+// called innermost first, so that the outer suite's are called last, and
+// the TearDownTest and TearDownSuite methods in the reverse order. Only
+// the layers whose set up was reached are torn down, and a layer that
+// fails is logged by name.
+//
+// Layers must not call the fixture methods of the suites they embed, as
+// the runner already does so.
+type ChainedSuite interface {
+	ChainFixtures() bool
+}
+
 // -----------------------------------------------------------------------
 // Public running interface.
 
-var (
-	newListFlag = flag.Bool("tc.list", false, "List the names of all tests that will be run")
-)
-
 // InternalTestingT runs all test suites registered with the Suite function,
 // printing results to stdout, and reporting any failures back to
-// the "testing" package.
+// the "testing" package. With the -tc.list flag the names of the tests are
+// printed instead, limited to those in the shard given by -tc.shard and
+// selected by -tc.tags and -tc.exclude-tags. With -tc.list=json, a JSON
+// array is printed giving the suite, method, package, source location,
+// fixtures, requirements and tags of each test.
 // Deprecated: prefer to write a standard test function per suite and call Run.
 func InternalTestingT(t *testing.T) {
 	t.Helper()
-	if *newListFlag {
-		w := bufio.NewWriter(os.Stdout)
-		for _, name := range ListAll() {
-			fmt.Fprintln(w, name)
+	if newListFlag != listOff {
+		if err := listAll(os.Stdout, newListFlag); err != nil {
+			t.Fatal(err)
 		}
-		w.Flush()
 		return
 	}
 	RunAll(t)
 }
 
 // RunAll runs all test suites registered with the Suite function, using the
-// provided run configuration.
+// provided run configuration. With the -tc.shuffle flag, the suites and the
+// tests in each suite are run in an order chosen by the printed seed. With
+// the -tc.shard flag, only the tests in the given shard are run, and with
+// -tc.tags and -tc.exclude-tags only those with matching tags, and the
+// others are skipped. A suite left with no tests to run is skipped without
+// calling its fixtures. With the -tc.slowest flag, the slowest tests and
+// fixtures of all the suites are printed once they have all finished.
 func RunAll(t *testing.T) {
 	t.Helper()
-	for _, suite := range allSuites {
+	if shuffleFlag.enabled {
+		fmt.Fprintf(t.Output(), "suites shuffled with -tc.shuffle=%d\n", shuffleFlag.seed)
+	}
+	if *slowestFlag > 0 {
+		reportMu.Lock()
+		start := len(reportedSuites)
+		reportMu.Unlock()
+		t.Cleanup(func() {
+			printSlowestSince(start)
+		})
+	}
+	for _, suite := range shuffled(allSuites) {
 		t.Run(suiteName(suite), func(t *testing.T) {
-			Run(t, suite)
+			runner := newSuiteRunner(suite)
+			runner.inRunAll = true
+			runner.run(t)
 		})
 	}
 }
 
 // Run runs the provided test suite using the provided run configuration.
+// As with RunAll, tests outside the shard given by -tc.shard, or not
+// selected by the -tc.tags and -tc.exclude-tags flags, are skipped, and the
+// slowest tests are printed at the end with -tc.slowest. Failed tests are
+// run again as many times as -tc.retries gives, or as RetrySuite and the
+// "flaky" tag ask for. With -tc.stress=N, each test is instead run N
+// times against fresh copies of the suite, stopping at the first failure.
+//
+// Test and fixture methods take a *C or *testing.T, optionally preceded by
+// a context.Context, and may return an error. A non-nil error stops the
+// test as a failed c.Assert(err, ErrorIsNil) would, except one returned by
+// a TearDown fixture, which fails the test without stopping the fixtures
+// chained after it. The context passed to a test method is cancelled as soon as
+// it returns, before TearDownTest is called.
 func Run(t *testing.T, suite any) {
 	t.Helper()
 	runner := newSuiteRunner(suite)
@@ -91,17 +142,19 @@ func Run(t *testing.T, suite any) {
 }
 
 // ListAll returns the names of all the test functions registered with the
-// Suite function that will be run with the provided run configuration.
+// Suite function that will be run with the provided run configuration, in
+// the order RunAll runs them.
 func ListAll() []string {
 	var names []string
-	for _, suite := range allSuites {
+	for _, suite := range shuffled(allSuites) {
 		names = append(names, List(suite)...)
 	}
 	return names
 }
 
 // List returns the names of the test functions in the given
-// suite that will be run with the provided run configuration.
+// suite that will be run with the provided run configuration, in the order
+// Run runs them.
 func List(suite any) []string {
 	var names []string
 	runner := newSuiteRunner(suite)
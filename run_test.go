@@ -27,6 +27,17 @@
 package tc_test
 
 import (
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
 	. "github.com/juju/tc"
 )
 
@@ -62,18 +73,36 @@ func (s *RunS) TestFixture(c *C) {
 
 func (s *RunS) TestPanicOnTest(c *C) {
 	exitCode, output := runHelperSuite("FixtureHelper", "-helper.panic", "Test1")
-	c.Check(exitCode, Equals, 2)
+	c.Check(exitCode, Equals, 1)
 	c.Check(output.Status("Test1"), Equals, "FAIL")
-	// stdlib testing stops on first panic
-	c.Check(output.Status("Test2"), Equals, "")
+	c.Check(output.Logs("Test1"), Matches, `(?s)\s*/.*/check_test.go:\d+\n`+
+		`\s+/.*/check_test.go:\d+\n`+
+		`\s+panic\(name\)\n`+
+		`\s+\.\.\. Panic: Test1\n`+
+		`\s*\n`+
+		`\s+/.*/check_test.go:\d+\n`+
+		`\s+in tc_test.\(\*FixtureHelper\).trace\n`+
+		`\s+/.*/check_test.go:\d+\n`+
+		`\s+in tc_test.\(\*FixtureHelper\).Test1`)
+	// The suite carries on with the next test.
+	c.Check(output.Status("Test2"), Equals, "PASS")
 }
 
 func (s *RunS) TestPanicOnSetUpTest(c *C) {
 	exitCode, output := runHelperSuite("FixtureHelper", "-helper.panic", "SetUpTest")
-	c.Check(exitCode, Equals, 2)
+	c.Check(exitCode, Equals, 1)
 	c.Check(output.Status("Test1"), Equals, "FAIL")
-	// stdlib testing stops on first panic
-	c.Check(output.Status("Test2"), Equals, "")
+	c.Check(output.Logs("Test1"), Matches, `(?s).*\.\.\. Panic: SetUpTest\n.*`)
+	// SetUpTest panics for every test.
+	c.Check(output.Status("Test2"), Equals, "FAIL")
+}
+
+func (s *RunS) TestPanicOnTearDownTest(c *C) {
+	exitCode, output := runHelperSuite("FixtureHelper", "-helper.panic", "TearDownTest")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("Test1"), Equals, "FAIL")
+	c.Check(output.Logs("Test1"), Matches, `(?s).*\.\.\. Panic: TearDownTest\n.*`)
+	c.Check(output.Status("Test2"), Equals, "FAIL")
 }
 
 func (s *RunS) TestPanicOnSetUpSuite(c *C) {
@@ -84,6 +113,735 @@ func (s *RunS) TestPanicOnSetUpSuite(c *C) {
 	c.Check(output.Status("Test2"), Equals, "")
 }
 
+// -----------------------------------------------------------------------
+// Tests ensuring parallel suites run correctly.
+
+func (s *RunS) TestParallel(c *C) {
+	suite := &ParallelHelper{}
+	c.T.Run("ParallelHelper", func(t *testing.T) {
+		Run(t, suite)
+	})
+	c.Assert(suite.events, NotNil)
+	c.Check(suite.events.events, HasLen, 4)
+	c.Check(suite.events.events[0], Equals, "SetUpSuite")
+	c.Check(suite.events.events[1:3], SameContents, []string{
+		"Test/RunS/TestParallel/ParallelHelper/Test1",
+		"Test/RunS/TestParallel/ParallelHelper/Test2",
+	})
+	c.Check(suite.events.events[3], Equals, "TearDownSuite")
+	// Tests run against copies of the suite.
+	c.Check(suite.name, Equals, "")
+
+	exitCode, output := runHelperSuite("ParallelHelper")
+	c.Check(exitCode, Equals, 0)
+	c.Check(output.Status("Test1"), Equals, "PASS")
+	c.Check(output.Status("Test2"), Equals, "PASS")
+	c.Check(output.Paused("Test1"), Equals, true)
+	c.Check(output.Paused("Test2"), Equals, true)
+}
+
+// -----------------------------------------------------------------------
+// Tests ensuring fixtures of embedded suites are chained.
+
+func (s *RunS) TestChainedFixtures(c *C) {
+	suite := &ChainHelper{chained: true}
+	c.T.Run("ChainHelper", func(t *testing.T) {
+		Run(t, suite)
+	})
+	c.Check(*suite.calls, DeepEquals, []string{
+		"ChainBase.SetUpSuite",
+		"ChainBase.SetUpTest",
+		"ChainMiddle.SetUpTest",
+		"ChainHelper.SetUpTest",
+		"Test1",
+		"ChainMiddle.TearDownTest",
+		"ChainBase.TearDownTest",
+		"ChainBase.TearDownSuite",
+	})
+}
+
+func (s *RunS) TestUnchainedFixtures(c *C) {
+	suite := &ChainHelper{}
+	c.T.Run("ChainHelper", func(t *testing.T) {
+		Run(t, suite)
+	})
+	c.Check(*suite.calls, DeepEquals, []string{
+		"ChainBase.SetUpSuite",
+		"ChainHelper.SetUpTest",
+		"Test1",
+		"ChainMiddle.TearDownTest",
+		"ChainBase.TearDownSuite",
+	})
+}
+
+func (s *RunS) TestChainedFixtureFailure(c *C) {
+	exitCode, output := runHelperSuite("ChainHelper", "-helper.fail", "ChainMiddle.SetUpTest")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("Test1"), Equals, "FAIL")
+	c.Check(output.Logs("Test1"), Matches, `(?s).*failing ChainMiddle.SetUpTest\n.*fixture ChainMiddle.SetUpTest failed`)
+}
+
+// -----------------------------------------------------------------------
+// Tests ensuring hung tests time out.
+
+func (s *RunS) TestTimeoutInTest(c *C) {
+	exitCode, output := runHelperSuite("TimeoutHelper", "-helper.hang", "Test1")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("Test1"), Equals, "FAIL")
+	c.Check(output.Logs("Test1"), Matches, `(?s).*TimeoutHelper.Test1 timed out after 100ms in test body\n.*`)
+	c.Check(output.Logs("Test1"), Matches, `(?s).*\(\*TimeoutHelper\).hang\(.*`)
+	c.Check(output.Logs("Test1"), Not(Matches), `(?s).*testing.tRunner.*`)
+	// The runner found the failure, so no line of its own is blamed.
+	c.Check(output.Logs("Test1"), Not(Matches), `(?s).*\.go:\d+: TimeoutHelper.Test1 timed out.*`)
+	c.Check(output.Status("Test2"), Equals, "PASS")
+}
+
+func (s *RunS) TestTimeoutInFixture(c *C) {
+	exitCode, output := runHelperSuite("TimeoutHelper", "-helper.hang", "TearDownTest")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("Test1"), Equals, "FAIL")
+	c.Check(output.Logs("Test1"), Matches, `(?s).*TimeoutHelper.Test1 timed out after 100ms in TearDownTest\n.*`)
+	c.Check(output.Status("Test2"), Equals, "FAIL")
+}
+
+func (s *RunS) TestTimeoutDropsLateReports(c *C) {
+	exitCode, output := runHelperSuite("TimeoutHelper", "-helper.hang", "TestLate")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("TestLate"), Equals, "FAIL")
+	c.Check(output.Logs("TestLate"), Matches, `(?s).*TimeoutHelper.TestLate timed out after 100ms in test body\n.*`)
+	all := strings.Join(output, "\n")
+	c.Check(all, Not(Matches), `(?s).*after timing out.*`)
+	c.Check(all, Not(Matches), `(?s).*has completed.*`)
+}
+
+func (s *RunS) TestTimeoutPassesOnFailure(c *C) {
+	exitCode, output := runHelperSuite("TimeoutHelper", "-helper.hang", "Test2")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("Test1"), Equals, "PASS")
+	c.Check(output.Status("Test2"), Equals, "FAIL")
+	c.Check(output.Logs("Test2"), Not(Matches), `(?s).*timed out.*`)
+}
+
+// -----------------------------------------------------------------------
+// Tests ensuring leaked goroutines are reported.
+
+func (s *RunS) TestLeaks(c *C) {
+	exitCode, output := runHelperSuite("LeakHelper")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("TestLeak"), Equals, "FAIL")
+	c.Check(output.Logs("TestLeak"), Matches, `(?s).*TestHelperSuite/TestLeak leaked 1 goroutine\(s\):\n.*\(\*LeakHelper\).leakedWorker\(.*`)
+	c.Check(output.Logs("TestLeak"), Not(Matches), `(?s).*\.go:\d+: TestHelperSuite/TestLeak leaked.*`)
+	c.Check(output.Status("TestIgnoredLeak"), Equals, "PASS")
+	c.Check(output.Status("TestSlowGoroutine"), Equals, "PASS")
+	c.Check(output.Status("TestStoppedInCleanup"), Equals, "PASS")
+}
+
+func (s *RunS) TestReport(c *C) {
+	dir := c.MkDir()
+	jsonPath := filepath.Join(dir, "report.json")
+	junitPath := filepath.Join(dir, "report.xml")
+	exitCode, _ := runHelperSuite("ReportHelper", "-tc.report=json:"+jsonPath+",junit:"+junitPath)
+	c.Check(exitCode, Equals, 1)
+
+	data, err := os.ReadFile(jsonPath)
+	c.Assert(err, IsNil)
+	var suites []struct {
+		Name    string
+		Package string
+		Tests   []struct {
+			Name       string
+			Status     string
+			Fixtures   map[string]float64
+			SkipReason string `json:"skip_reason"`
+			Failures   []string
+		}
+	}
+	c.Assert(json.Unmarshal(data, &suites), IsNil)
+	c.Assert(suites, HasLen, 1)
+	c.Check(suites[0].Name, Equals, "ReportHelper")
+	c.Check(suites[0].Package, Equals, "github.com/juju/tc_test")
+	tests := suites[0].Tests
+	c.Assert(tests, HasLen, 3)
+	c.Check(tests[0].Name, Equals, "TestFail")
+	c.Check(tests[0].Status, Equals, "fail")
+	c.Assert(tests[0].Failures, HasLen, 1)
+	c.Check(tests[0].Failures[0], Matches, `(?s).*obtained int = 1\n.*expected int = 2.*`)
+	c.Check(tests[1].Name, Equals, "TestPass")
+	c.Check(tests[1].Status, Equals, "pass")
+	c.Check(tests[1].Fixtures, HasLen, 3)
+	c.Check(tests[2].Name, Equals, "TestSkip")
+	c.Check(tests[2].Status, Equals, "skip")
+	c.Check(tests[2].SkipReason, Equals, "not today")
+
+	data, err = os.ReadFile(junitPath)
+	c.Assert(err, IsNil)
+	var junit struct {
+		Suites []struct {
+			Name     string `xml:"name,attr"`
+			Tests    int    `xml:"tests,attr"`
+			Failures int    `xml:"failures,attr"`
+			Skipped  int    `xml:"skipped,attr"`
+			Cases    []struct {
+				Name    string `xml:"name,attr"`
+				Failure *struct {
+					Text string `xml:",chardata"`
+				} `xml:"failure"`
+				Skipped *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"skipped"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	c.Assert(xml.Unmarshal(data, &junit), IsNil)
+	c.Assert(junit.Suites, HasLen, 1)
+	suite := junit.Suites[0]
+	c.Check(suite.Name, Equals, "ReportHelper")
+	c.Check(suite.Tests, Equals, 3)
+	c.Check(suite.Failures, Equals, 1)
+	c.Check(suite.Skipped, Equals, 1)
+	c.Assert(suite.Cases, HasLen, 3)
+	c.Check(suite.Cases[0].Failure, NotNil)
+	c.Check(suite.Cases[1].Failure, IsNil)
+	c.Check(suite.Cases[2].Skipped, NotNil)
+	c.Check(suite.Cases[2].Skipped.Message, Equals, "not today")
+}
+
+// setShuffle sets the -tc.shuffle flag for the rest of the test.
+func setShuffle(c *C, value string) {
+	old := flag.Lookup("tc.shuffle").Value.String()
+	c.Assert(flag.Set("tc.shuffle", value), IsNil)
+	c.Cleanup(func() {
+		flag.Set("tc.shuffle", old)
+	})
+}
+
+func (s *RunS) TestShuffle(c *C) {
+	setShuffle(c, "42")
+	shuffled := List(&ShuffleHelper{})
+	c.Check(List(&ShuffleHelper{}), DeepEquals, shuffled)
+	c.Check(shuffled, Not(DeepEquals), []string{
+		"ShuffleHelper.TestA", "ShuffleHelper.TestB", "ShuffleHelper.TestC", "ShuffleHelper.TestD",
+		"ShuffleHelper.TestE", "ShuffleHelper.TestF", "ShuffleHelper.TestG", "ShuffleHelper.TestH",
+	})
+
+	exitCode, output := runHelperSuite("ShuffleHelper", "-tc.shuffle=42")
+	c.Check(exitCode, Equals, 0)
+	c.Check(strings.Join(output, "\n"), Matches, `(?s).*tests shuffled with -tc.shuffle=42\n.*`)
+	var want []string
+	for _, name := range shuffled {
+		want = append(want, strings.TrimPrefix(name, "ShuffleHelper."))
+	}
+	c.Check(output.Started(), DeepEquals, want)
+}
+
+func (s *RunS) TestShuffleRandomSeed(c *C) {
+	setShuffle(c, "off")
+	c.Check(List(&ShuffleHelper{}), DeepEquals, []string{
+		"ShuffleHelper.TestA", "ShuffleHelper.TestB", "ShuffleHelper.TestC", "ShuffleHelper.TestD",
+		"ShuffleHelper.TestE", "ShuffleHelper.TestF", "ShuffleHelper.TestG", "ShuffleHelper.TestH",
+	})
+	exitCode, output := runHelperSuite("ShuffleHelper", "-tc.shuffle=on")
+	c.Check(exitCode, Equals, 0)
+	c.Check(strings.Join(output, "\n"), Matches, `(?s).*tests shuffled with -tc.shuffle=-?\d+\n.*`)
+}
+
+func (s *RunS) TestShard(c *C) {
+	passed := make(map[string]int)
+	for i, shard := range []string{"0/2", "1/2"} {
+		exitCode, output := runHelperSuite("ShuffleHelper", "-tc.shard="+shard)
+		c.Check(exitCode, Equals, 0)
+		for _, name := range output.Started() {
+			switch output.Status(name) {
+			case "PASS":
+				passed[name]++
+			case "SKIP":
+				c.Check(output.Logs(name), Matches, `\s*not in shard `+shard)
+			default:
+				c.Errorf("%s in shard %d: %s", name, i, output.Status(name))
+			}
+		}
+	}
+	c.Check(passed, DeepEquals, map[string]int{
+		"TestA": 1, "TestB": 1, "TestC": 1, "TestD": 1,
+		"TestE": 1, "TestF": 1, "TestG": 1, "TestH": 1,
+	})
+}
+
+func (s *RunS) TestShardFromEnvironment(c *C) {
+	c.Setenv("TC_SHARD_INDEX", "1")
+	c.Setenv("TC_SHARD_TOTAL", "2")
+	_, fromEnv := runHelperSuite("ShuffleHelper")
+	_, fromFlag := runHelperSuite("ShuffleHelper", "-tc.shard=1/2")
+	for _, name := range fromFlag.Started() {
+		c.Check(fromEnv.Status(name), Equals, fromFlag.Status(name))
+	}
+
+	c.Setenv("TC_SHARD_INDEX", "2")
+	exitCode, output := runHelperSuite("ShuffleHelper")
+	c.Check(exitCode, Equals, 1)
+	c.Check(strings.Join(output, "\n"), Matches, `(?s).*\$TC_SHARD_INDEX/\$TC_SHARD_TOTAL: shard 2/2 is out of range.*`)
+}
+
+func (s *RunS) TestShardList(c *C) {
+	_, all := runHelper("Test", "", "-tc.list")
+	_, shard0 := runHelper("Test", "", "-tc.list", "-tc.shard=0/2")
+	_, shard1 := runHelper("Test", "", "-tc.list", "-tc.shard=1/2")
+	names := func(output helperResult) []string {
+		var names []string
+		for _, line := range output {
+			if name, _, _ := strings.Cut(line, " ["); strings.Contains(name, ".Test") && !strings.Contains(name, " ") {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	c.Assert(len(names(all)) > 0, IsTrue)
+	c.Check(len(names(shard0)) > 0, IsTrue)
+	c.Check(len(names(shard1)) > 0, IsTrue)
+	c.Check(append(names(shard0), names(shard1)...), SameContents, names(all))
+}
+
+func (s *RunS) TestSlowest(c *C) {
+	exitCode, output := runHelperSuite("TimingHelper", "-tc.slowest=2")
+	c.Check(exitCode, Equals, 0)
+	// The times depend on the machine, so only their lower bounds and the
+	// order of the tests are checked.
+	const d = `(\d+\.\d{3}s)`
+	match := regexp.MustCompile(`\n` +
+		`slowest 2 of 5:\n` +
+		` *` + d + `  TimingHelper\.TestSlow \(SetUpTest ` + d + `, test body ` + d + `, TearDownTest ` + d + `\)\n` +
+		` *` + d + `  TimingHelper\.TestSlowFixture \(SetUpTest ` + d + `, test body ` + d + `, TearDownTest ` + d + `\)\n` +
+		`time spent in each phase: SetUpSuite ` + d + `, SetUpTest ` + d + `, test body ` + d + `, TearDownTest ` + d + `, TearDownSuite ` + d + `\n`,
+	).FindStringSubmatch(strings.Join(output, "\n"))
+	c.Assert(match, NotNil, Commentf("%s", strings.Join(output, "\n")))
+	duration := func(i int) time.Duration {
+		d, err := time.ParseDuration(match[i])
+		c.Assert(err, IsNil)
+		return d
+	}
+	c.Check(duration(1), Not(DurationLessThan), 100*time.Millisecond)
+	c.Check(duration(3), Not(DurationLessThan), 100*time.Millisecond)
+	c.Check(duration(5), Not(DurationLessThan), 50*time.Millisecond)
+	c.Check(duration(6), Not(DurationLessThan), 50*time.Millisecond)
+	c.Check(duration(10), Not(DurationLessThan), 50*time.Millisecond)
+	c.Check(duration(11), Not(DurationLessThan), 100*time.Millisecond)
+}
+
+func (s *RunS) TestBudgets(c *C) {
+	exitCode, output := runHelperSuite("TimingHelper", "-tc.budget=80ms")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("TestSlow"), Equals, "FAIL")
+	c.Check(output.Logs("TestSlow"), Matches, `(?s).*TestHelperSuite/TestSlow took \d+(\.\d+)?m?s in test body, over the budget of 80ms.*`)
+	c.Check(output.Logs("TestSlow"), Not(Matches), `(?s).*\.go:\d+: TestHelperSuite/TestSlow took.*`)
+	c.Check(output.Status("TestSlowFixture"), Equals, "PASS")
+	c.Check(output.Status("TestFast"), Equals, "PASS")
+
+	exitCode, output = runHelperSuite("TimingHelper", "-tc.fixture-budget=30ms")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("TestSlow"), Equals, "PASS")
+	c.Check(output.Status("TestSlowFixture"), Equals, "FAIL")
+	c.Check(output.Logs("TestSlowFixture"), Matches, `(?s).*TestHelperSuite/TestSlowFixture took \d+(\.\d+)?m?s in SetUpTest, over the budget of 30ms.*`)
+	c.Check(output.Status("TestFast"), Equals, "PASS")
+}
+
+func (s *RunS) TestRequirements(c *C) {
+	exitCode, output := runHelperSuite("RequiresHelper")
+	c.Check(exitCode, Equals, 0)
+	c.Check(output.Status("TestMet"), Equals, "PASS")
+	c.Check(output.Status("TestUnmet"), Equals, "SKIP")
+	c.Check(output.Logs("TestUnmet"), Matches, `\s*unmet requirements: `+
+		`\$TC_NO_SUCH_VARIABLE is not set; tc-no-such-binary not found on \$PATH; running on \w+, not plan9`)
+	c.Check(output.Logs("TestUnmet"), Not(Matches), `(?s).*SetUpTest.*`)
+}
+
+func (s *RunS) TestNoTestsSelected(c *C) {
+	setUpSuite := `(?s).*check_test.go:\d+: SetUpSuite.*`
+	for _, shard := range []string{"0/2", "1/2"} {
+		exitCode, output := runHelperSuite("RequiresHelper", "-tc.shard="+shard)
+		c.Check(exitCode, Equals, 0)
+		c.Check(output.Status("TestUnmet"), Equals, "SKIP")
+		joined := strings.Join(output, "\n")
+		if output.Status("TestMet") == "PASS" {
+			c.Check(joined, Matches, setUpSuite)
+			continue
+		}
+		c.Check(output.Status("TestMet"), Equals, "SKIP")
+		c.Check(joined, Not(Matches), setUpSuite)
+		c.Check(joined, Matches, `(?s).*no tests selected to run, so the suite's fixtures are not run.*`)
+	}
+
+	exitCode, output := runHelperSuite("RequiresHelper", "-tc.tags=none")
+	c.Check(exitCode, Equals, 0)
+	c.Check(output.Status("TestMet"), Equals, "SKIP")
+	c.Check(output.Logs("TestMet"), Matches, `\s*not selected by -tc.tags=none`)
+	c.Check(output.Status("TestUnmet"), Equals, "SKIP")
+	c.Check(strings.Join(output, "\n"), Not(Matches), setUpSuite)
+}
+
+func (s *RunS) TestSuiteRequirements(c *C) {
+	exitCode, output := runHelperSuite("RequiresHelper", "-helper.fail=plan9")
+	c.Check(exitCode, Equals, 0)
+	c.Check(output.Started(), HasLen, 0)
+	c.Check(strings.Join(output, "\n"), Matches, `(?s).*\n\s+unmet requirements: running on \w+, not plan9\n--- SKIP: TestHelperSuite .*`)
+	c.Check(strings.Join(output, "\n"), Not(Matches), `(?s).*SetUpSuite.*`)
+}
+
+func (s *RunS) TestForceRun(c *C) {
+	exitCode, output := runHelperSuite("RequiresHelper", "-tc.force-run")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("TestMet"), Equals, "PASS")
+	c.Check(output.Status("TestUnmet"), Equals, "FAIL")
+	c.Check(output.Logs("TestUnmet"), Matches, `(?s)\s*running with -tc.force-run despite unmet requirements: .*SetUpTest.*`)
+}
+
+func (s *RunS) TestContextMethods(c *C) {
+	suite := &SignatureHelper{}
+	c.T.Run("SignatureHelper", func(t *testing.T) {
+		Run(t, suite)
+	})
+	c.Check(suite.ctx, NotNil)
+	c.Check(suite.cancelledBeforeTD, IsTrue)
+	c.Check(suite.cases, DeepEquals, []int{1, 2})
+}
+
+func (s *RunS) TestErrorMethods(c *C) {
+	exitCode, output := runHelperSuite("SignatureHelper", "-helper.fail=yes")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("TestNilError"), Equals, "PASS")
+	c.Check(output.Status("TestError"), Equals, "FAIL")
+	c.Check(output.Logs("TestError"), Matches, `\s*/.*/check_test.go:\d+\n`+
+		`\s*/.*/check_test.go:\d+\n`+
+		`\s*\.\.\. TestError returned an error\n`+
+		`\s*\.\.\. value tc_test.stackError = tc_test.stackError{} \("failed with a stack"\)\n`+
+		`\s*\.\.\. error stack:\n`+
+		`\s*first.go:1\n`+
+		`\s*second.go:2`)
+}
+
+func (s *RunS) TestFixtureErrorsStopTheTest(c *C) {
+	exitCode, output := runHelperSuite("FixtureErrorHelper", "-helper.fail=SetUpSuite")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("TestBody"), Equals, "")
+	all := strings.Join(output, "\n")
+	c.Check(all, Matches, `(?s).*SetUpSuite returned an error.*TearDownSuite ran.*`)
+
+	exitCode, output = runHelperSuite("FixtureErrorHelper", "-helper.fail=SetUpTest")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("TestBody"), Equals, "FAIL")
+	c.Check(output.Logs("TestBody"), Matches, `(?s).*SetUpTest returned an error.*TearDownTest ran.*`)
+	c.Check(output.Logs("TestBody"), Not(Matches), `(?s).*TestBody ran.*`)
+
+	// A tear down fixture's error doesn't stop the fixtures after it.
+	exitCode, output = runHelperSuite("FixtureErrorHelper", "-helper.fail=TearDownTest")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("TestBody"), Equals, "FAIL")
+	c.Check(strings.Join(output, "\n"), Matches, `(?s).*TearDownTest returned an error.*TearDownSuite ran.*`)
+}
+
+func (s *RunS) TestListTests(c *C) {
+	tests := ListTests(&RequiresHelper{suiteOS: "linux"})
+	c.Assert(tests, HasLen, 2)
+	c.Check(tests[0].Name, Equals, "RequiresHelper.TestMet")
+	c.Check(tests[0].Suite, Equals, "RequiresHelper")
+	c.Check(tests[0].Method, Equals, "TestMet")
+	c.Check(tests[0].Package, Equals, "github.com/juju/tc_test")
+	c.Check(tests[0].File, Matches, ".*/check_test.go")
+	c.Check(tests[0].Line > 0, IsTrue)
+	c.Check(tests[0].Fixtures, DeepEquals, []string{"SetUpSuite", "SetUpTest"})
+	c.Check(tests[0].Requirements, DeepEquals, []string{"GOOS linux", "$PATH", "GOOS " + runtime.GOOS})
+	c.Check(tests[1].Name, Equals, "RequiresHelper.TestUnmet")
+	c.Check(tests[1].Requirements, DeepEquals, []string{
+		"GOOS linux", "$TC_NO_SUCH_VARIABLE", "binary tc-no-such-binary", "GOOS plan9",
+	})
+}
+
+func (s *RunS) TestListJSON(c *C) {
+	_, output := runHelper("Test", "", "-tc.list=json")
+	joined := strings.Join(output, "\n")
+	start, end := strings.Index(joined, "\n[\n"), strings.Index(joined, "\n]\n")
+	c.Assert(start >= 0 && end > start, IsTrue, Commentf("%s", joined))
+	var tests []struct {
+		Name     string
+		Suite    string
+		Method   string
+		Package  string
+		File     string
+		Line     int
+		Fixtures []string
+	}
+	c.Assert(json.Unmarshal([]byte(joined[start:end+2]), &tests), IsNil)
+	found := false
+	for _, test := range tests {
+		if test.Name != "SkippedSuite.TestShouldFail" {
+			continue
+		}
+		found = true
+		c.Check(test.Suite, Equals, "SkippedSuite")
+		c.Check(test.Method, Equals, "TestShouldFail")
+		c.Check(test.Package, Equals, "github.com/juju/tc_test")
+		c.Check(test.File, Matches, ".*/check_test.go")
+		c.Check(test.Fixtures, DeepEquals, []string{"SetUpSuite", "TearDownSuite", "SetUpTest", "TearDownTest"})
+	}
+	c.Check(found, IsTrue)
+
+	// The plain listing is unchanged.
+	_, output = runHelper("Test", "", "-tc.list")
+	c.Check(strings.Join(output, "\n"), Matches, "(?s).*\nSkippedSuite.TestShouldFail\n.*")
+}
+
+func (s *RunS) TestTags(c *C) {
+	exitCode, output := runHelperSuite("TagHelper", "-tc.tags=slow,db+!flaky")
+	c.Check(exitCode, Equals, 0)
+	c.Check(output.Status("TestSlow"), Equals, "PASS")
+	c.Check(output.Status("TestDB"), Equals, "PASS")
+	c.Check(output.Status("TestFlakyDB"), Equals, "SKIP")
+	c.Check(output.Logs("TestFlakyDB"), Matches, `\s*not selected by -tc.tags=slow,db\+!flaky`)
+	c.Check(output.Status("TestPlain"), Equals, "SKIP")
+
+	exitCode, output = runHelperSuite("TagHelper", "-tc.tags=unit", "-tc.exclude-tags=flaky,slow")
+	c.Check(exitCode, Equals, 0)
+	c.Check(output.Status("TestSlow"), Equals, "SKIP")
+	c.Check(output.Logs("TestSlow"), Matches, `\s*excluded by -tc.exclude-tags=flaky,slow`)
+	c.Check(output.Status("TestDB"), Equals, "PASS")
+	c.Check(output.Status("TestFlakyDB"), Equals, "SKIP")
+	c.Check(output.Status("TestPlain"), Equals, "PASS")
+}
+
+func (s *RunS) TestTagExpressions(c *C) {
+	defer flag.Set("tc.tags", "")
+	c.Check(flag.Set("tc.tags", "a+"), ErrorMatches, `empty tag in "a\+"`)
+	c.Check(flag.Set("tc.tags", "a,,b"), ErrorMatches, `empty tag in "a,,b"`)
+	c.Check(flag.Set("tc.tags", "a+!b, c"), IsNil)
+}
+
+func (s *RunS) TestTagsListedAndReported(c *C) {
+	tests := ListTests(&TagHelper{})
+	c.Assert(tests, HasLen, 4)
+	c.Check(tests[0].Method, Equals, "TestDB")
+	c.Check(tests[0].Tags, DeepEquals, []string{"unit", "db"})
+	c.Check(tests[1].Method, Equals, "TestFlakyDB")
+	c.Check(tests[1].Tags, DeepEquals, []string{"unit", "db", "flaky"})
+	c.Check(tests[2].Method, Equals, "TestPlain")
+	c.Check(tests[2].Tags, DeepEquals, []string{"unit"})
+	c.Check(tests[1].String(), Equals, "TagHelper.TestFlakyDB [unit,db,flaky]")
+
+	path := filepath.Join(c.MkDir(), "report.json")
+	exitCode, _ := runHelperSuite("TagHelper", "-tc.report=json:"+path)
+	c.Check(exitCode, Equals, 0)
+	data, err := os.ReadFile(path)
+	c.Assert(err, IsNil)
+	var suites []struct {
+		Tests []struct {
+			Name string
+			Tags []string
+		}
+	}
+	c.Assert(json.Unmarshal(data, &suites), IsNil)
+	c.Assert(suites, HasLen, 1)
+	c.Assert(suites[0].Tests, HasLen, 4)
+	c.Check(suites[0].Tests[1].Name, Equals, "TestFlakyDB")
+	c.Check(suites[0].Tests[1].Tags, DeepEquals, []string{"unit", "db", "flaky"})
+}
+
+func (s *RunS) TestRetries(c *C) {
+	exitCode, output := runHelperSuite("RetryHelper")
+	c.Check(exitCode, Equals, 1)
+
+	c.Check(output.Status("TestFlaky"), Equals, "PASS")
+	c.Check(output.Logs("TestFlaky"), Matches, `(?s).*`+
+		`attempt 1 of 3: FAIL\s+check_test.go:\d+: set up attempt 1\s+.*`+
+		`tear down attempt 1\s+`+
+		`attempt 2 of 3: PASS\s+check_test.go:\d+: set up attempt 2\s+`+
+		`check_test.go:\d+: tear down attempt 2\s+`+
+		`flaky test passed on attempt 2 of 3`)
+
+	c.Check(output.Status("TestBroken"), Equals, "FAIL")
+	c.Check(output.Logs("TestBroken"), Matches, `(?s).*`+
+		`attempt 1 of 3: FAIL.*broken on attempt 1.*`+
+		`attempt 2 of 3: FAIL.*broken on attempt 2.*`+
+		`attempt 3 of 3: FAIL.*broken on attempt 3.*`)
+
+	c.Check(output.Status("TestQuarantined"), Equals, "PASS")
+	c.Check(output.Logs("TestQuarantined"), Matches, `(?s).*`+
+		`attempt 3 of 3: FAIL.*always fails.*`+
+		`quarantined test failed, not failing the run`)
+
+	c.Check(output.Status("TestOnce"), Equals, "FAIL")
+	c.Check(output.Logs("TestOnce"), Not(Matches), `(?s).*attempt \d+ of.*`)
+}
+
+func (s *RunS) TestRetriedAttemptsRestoreEnv(c *C) {
+	exitCode, output := runHelperSuite("RetryEnvHelper")
+	c.Check(exitCode, Equals, 0)
+	c.Check(output.Status("TestEnv"), Equals, "PASS")
+	c.Check(output.Logs("TestEnv"), Matches, `(?s).*`+
+		`attempt 1 of 2: FAIL\s+check_test.go:\d+: attempt 1: TC_RETRY_ENV=""\s+`+
+		`attempt 2 of 2: PASS\s+check_test.go:\d+: attempt 2: TC_RETRY_ENV=""\s+`+
+		`flaky test passed on attempt 2 of 2`)
+}
+
+func (s *RunS) TestRetriesReported(c *C) {
+	path := filepath.Join(c.MkDir(), "report.json")
+	runHelperSuite("RetryHelper", "-tc.report=json:"+path)
+	data, err := os.ReadFile(path)
+	c.Assert(err, IsNil)
+	var suites []struct {
+		Tests []struct {
+			Name     string
+			Status   string
+			Attempts int
+			Failures []string
+		}
+	}
+	c.Assert(json.Unmarshal(data, &suites), IsNil)
+	c.Assert(suites, HasLen, 1)
+	tests := suites[0].Tests
+	c.Assert(tests, HasLen, 4)
+
+	c.Check(tests[0].Name, Equals, "TestBroken")
+	c.Check(tests[0].Status, Equals, "fail")
+	c.Check(tests[0].Attempts, Equals, 3)
+	c.Check(tests[0].Failures, DeepEquals, []string{
+		"broken on attempt 1", "broken on attempt 2", "broken on attempt 3",
+	})
+	c.Check(tests[1].Name, Equals, "TestFlaky")
+	c.Check(tests[1].Status, Equals, "flaky")
+	c.Check(tests[1].Attempts, Equals, 2)
+	c.Check(tests[1].Failures, HasLen, 1)
+	c.Check(tests[2].Name, Equals, "TestOnce")
+	c.Check(tests[2].Status, Equals, "fail")
+	c.Check(tests[2].Attempts, Equals, 0)
+	c.Check(tests[3].Name, Equals, "TestQuarantined")
+	c.Check(tests[3].Status, Equals, "quarantined")
+	c.Check(tests[3].Attempts, Equals, 3)
+}
+
+func (s *RunS) TestRetriesFlag(c *C) {
+	exitCode, output := runHelperSuite("TagHelper", "-tc.retries=1")
+	c.Check(exitCode, Equals, 0)
+	c.Check(output.Status("TestPlain"), Equals, "PASS")
+	c.Check(output.Logs("TestPlain"), Not(Matches), `(?s).*attempt \d+ of.*`)
+}
+
+func (s *RunS) TestSyncTest(c *C) {
+	start := time.Now()
+	exitCode, output := runHelperSuite("SyncTestHelper")
+	c.Check(time.Since(start), DurationLessThan, time.Minute)
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("TestSleep"), Equals, "PASS")
+	c.Check(output.Logs("TestSleep"), Matches, `(?s).*torn down after 24h0m0s`)
+	c.Check(output.Status("TestSkip"), Equals, "SKIP")
+	c.Check(output.Logs("TestSkip"), Matches, `(?s).*skipped in the bubble.*`)
+	c.Check(output.Status("TestFail"), Equals, "FAIL")
+	c.Check(output.Logs("TestFail"), Matches, `(?s).*failed after 1m0s.*torn down after 1m0s`)
+	c.Check(output.Status("TestReal"), Equals, "PASS")
+}
+
+func (s *RunS) TestSyncTestTimedWithRealClock(c *C) {
+	exitCode, output := runHelperSuite("SyncTestHelper", "-tc.budget=10s", "-tc.slowest=1")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("TestSleep"), Equals, "PASS")
+	c.Check(output.Status("TestFail"), Equals, "FAIL")
+	c.Check(strings.Join(output, "\n"), Not(Matches), `(?s).*over the budget.*`)
+	c.Check(strings.Join(output, "\n"), Matches, `(?s).*slowest 1 of \d+:\n +\d\.\d{3}s  .*`)
+}
+
+func (s *RunS) TestSyncTestHangs(c *C) {
+	exitCode, output := runHelperSuite("SyncTestHangHelper")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("TestHang"), Equals, "FAIL")
+	c.Check(output.Logs("TestHang"), Matches, `(?s).*SyncTestHangHelper.TestHang timed out after 200ms in test body.*`)
+	c.Check(output.Status("TestDeadlock"), Equals, "FAIL")
+	c.Check(output.Logs("TestDeadlock"), Matches, `(?s).*deadlock: all goroutines in bubble are blocked in test body\s+`+
+		`goroutine \d+ \[chan receive \(durable\), synctest bubble \d+\]:.*SyncTestHangHelper\).TestDeadlock.*`)
+	c.Check(output.Status("TestNext"), Equals, "PASS")
+}
+
+func (s *RunS) TestStress(c *C) {
+	exitCode, output := runHelperSuite("StressHelper", "-tc.stress=20", "-helper.fail=7")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("TestCount"), Equals, "FAIL")
+	c.Check(output.Logs("TestCount"), Matches, `(?s).*stress: iteration 7 of 20 failed after .*`+
+		`check_test.go:\d+:\s+c.Check\(fmt.Sprint\(n\), tc.Not\(tc.Equals\), s.failAt\).*`)
+	c.Check(output.Status("TestPass"), Equals, "PASS")
+	c.Check(output.Logs("TestPass"), Matches, `(?s).*stress: 20 iterations with parallel=1 passed in .*`)
+	c.Check(strings.Join(output, "\n"), Matches, `(?s).*TestCount ran 7 times, TestPass ran 20 times.*`)
+}
+
+func (s *RunS) TestStressParallel(c *C) {
+	exitCode, output := runHelperSuite("StressHelper", "-tc.stress=50,parallel=4")
+	c.Check(exitCode, Equals, 0)
+	c.Check(output.Logs("TestCount"), Matches, `(?s).*stress: 50 iterations with parallel=4 passed in .*`)
+	c.Check(strings.Join(output, "\n"), Matches, `(?s).*TestCount ran 50 times, TestPass ran 50 times.*`)
+}
+
+func (s *RunS) TestStressRunsIterationsAsTests(c *C) {
+	exitCode, output := runHelperSuite("StressRunHelper", "-tc.stress=5")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("TestSleep"), Equals, "PASS")
+	c.Check(output.Logs("TestSleep"), Matches, `(?s).*stress: 5 iterations with parallel=1 passed in .*`)
+	c.Check(output.Status("TestRand"), Equals, "PASS")
+	c.Check(output.Status("TestHang"), Equals, "FAIL")
+	c.Check(output.Logs("TestHang"), Matches, `(?s).*StressRunHelper.TestHang timed out after 100ms in .*`+
+		`stress: iteration 2 of 5 timed out after .*`)
+}
+
+func (s *RunS) TestStressFlag(c *C) {
+	defer flag.Set("tc.stress", "")
+	c.Check(flag.Set("tc.stress", "0"), ErrorMatches, `invalid number of iterations "0"`)
+	c.Check(flag.Set("tc.stress", "5,parallel=0"), ErrorMatches, `invalid parallelism "0"`)
+	c.Check(flag.Set("tc.stress", "5,p=2"), ErrorMatches, `want N or N,parallel=P, got "5,p=2"`)
+	c.Check(flag.Set("tc.stress", "5,parallel=2"), IsNil)
+	c.Check(flag.Lookup("tc.stress").Value.String(), Equals, "5,parallel=2")
+}
+
+func (s *RunS) TestRand(c *C) {
+	exitCode, output := runHelperSuite("RandHelper")
+	c.Check(exitCode, Equals, 1)
+	logs := output.Logs("TestFail")
+	c.Assert(logs, Matches, `(?s).*drew -?\d+\s+c.Rand was seeded with -tc.seed=-?\d+`)
+	seed := regexp.MustCompile(`-tc.seed=(-?\d+)`).FindStringSubmatch(logs)[1]
+	drew := regexp.MustCompile(`drew (-?\d+)`).FindStringSubmatch(logs)[1]
+	c.Check(output.Logs("TestPass"), Not(Matches), `(?s).*seeded.*`)
+	c.Check(output.Status("TestUnused"), Equals, "FAIL")
+	c.Check(output.Logs("TestUnused"), Not(Matches), `(?s).*seeded.*`)
+
+	// The seed replays the test's numbers, whether or not it runs alone.
+	_, output = runHelper("TestHelperSuite/TestFail", "RandHelper", "-tc.seed="+seed)
+	c.Check(output.Logs("TestFail"), Matches, `(?s).*drew `+drew+`\s+c.Rand was seeded with -tc.seed=`+seed)
+
+	// Each test has its own numbers.
+	_, output = runHelperSuite("RandHelper", "-tc.seed=42")
+	first := regexp.MustCompile(`drew (-?\d+)`).FindStringSubmatch(output.Logs("TestFail"))[1]
+	second := regexp.MustCompile(`drew (-?\d+)`).FindStringSubmatch(output.Logs("TestPass"))[1]
+	c.Check(first, Not(Equals), second)
+}
+
+func (s *RunS) TestIsolationSuite(c *C) {
+	c.Setenv("TC_ISOLATION_SECRET", "secret")
+	c.Setenv("TC_ISOLATION_KEPT", "kept")
+	exitCode, output := runHelperSuite("IsolationHelper")
+	c.Check(exitCode, Equals, 1)
+	c.Check(output.Status("TestIsolated"), Equals, "PASS")
+	c.Check(output.Status("TestLeaks"), Equals, "FAIL")
+	c.Check(output.Logs("TestLeaks"), Matches, `(?s).*`+
+		`test leaked changes to the environment: TC_ISOLATION_LEAKED.*`+
+		`test leaked a change of working directory to ".*"`)
+	c.Check(output.Status("TestRestored"), Equals, "PASS")
+	c.Check(output.Status(""), Not(Equals), "FAIL")
+}
+
+func (s *RunS) TestIsolationSuiteParallel(c *C) {
+	exitCode, output := runHelperSuite("IsolationParallelHelper")
+	c.Check(exitCode, Equals, 1)
+	for _, name := range []string{"Test1", "Test2"} {
+		c.Check(output.Status(name), Equals, "FAIL")
+		c.Check(output.Logs(name), Matches, `(?s).*\n    IsolationSuite can't isolate the tests of a ParallelSuite, `+
+			`as they share the environment and working directory of the process`)
+		c.Check(output.Logs(name), Not(Matches), `(?s).* ran.*`)
+	}
+}
+
 /*
 // -----------------------------------------------------------------------
 // Verify that List works correctly.
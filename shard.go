@@ -0,0 +1,167 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func init() {
+	flag.Var(&shardFlag, "tc.shard", "Run only the suite tests in shard i of n, as i/n counting from 0; defaults to $TC_SHARD_INDEX/$TC_SHARD_TOTAL")
+}
+
+// The environment variables used to select a shard when the -tc.shard
+// flag isn't given.
+const (
+	shardIndexEnv = "TC_SHARD_INDEX"
+	shardTotalEnv = "TC_SHARD_TOTAL"
+)
+
+// shardFlag holds the value of the -tc.shard flag.
+var shardFlag shardValue
+
+// shard is one of a number of disjoint subsets of the suite tests,
+// chosen by a hash of their names as returned by List.
+type shard struct {
+	index, total int
+}
+
+func (s shard) String() string {
+	return fmt.Sprintf("%d/%d", s.index, s.total)
+}
+
+// contains reports whether the test with the given List name is in the
+// shard.
+func (s shard) contains(name string) bool {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return int(h.Sum32()%uint32(s.total)) == s.index
+}
+
+func parseShard(index, total string) (shard, error) {
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		return shard{}, fmt.Errorf("invalid shard index %q", index)
+	}
+	n, err := strconv.Atoi(total)
+	if err != nil {
+		return shard{}, fmt.Errorf("invalid shard total %q", total)
+	}
+	if n < 1 || i < 0 || i >= n {
+		return shard{}, fmt.Errorf("shard %d/%d is out of range", i, n)
+	}
+	return shard{index: i, total: n}, nil
+}
+
+// shardValue is a flag.Value holding the shard given by -tc.shard.
+type shardValue struct {
+	set   bool
+	shard shard
+}
+
+func (v *shardValue) String() string {
+	if !v.set {
+		return ""
+	}
+	return v.shard.String()
+}
+
+func (v *shardValue) Set(s string) error {
+	index, total, ok := strings.Cut(s, "/")
+	if !ok {
+		return fmt.Errorf("want i/n, got %q", s)
+	}
+	shard, err := parseShard(index, total)
+	if err != nil {
+		return err
+	}
+	*v = shardValue{set: true, shard: shard}
+	return nil
+}
+
+// activeShard returns the shard to run, from the -tc.shard flag or
+// failing that the environment, and whether tests are sharded at all.
+func activeShard() (shard, bool, error) {
+	if shardFlag.set {
+		return shardFlag.shard, true, nil
+	}
+	index, total := os.Getenv(shardIndexEnv), os.Getenv(shardTotalEnv)
+	if index == "" && total == "" {
+		return shard{}, false, nil
+	}
+	s, err := parseShard(index, total)
+	if err != nil {
+		return shard{}, false, fmt.Errorf("$%s/$%s: %v", shardIndexEnv, shardTotalEnv, err)
+	}
+	return s, true, nil
+}
+
+// inShard returns the names from the given list that are in the active
+// shard.
+func inShard(names []string) ([]string, error) {
+	s, ok, err := activeShard()
+	if err != nil || !ok {
+		return names, err
+	}
+	var result []string
+	for _, name := range names {
+		if s.contains(name) {
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+// skipTest skips a test the runner won't run, recording it as skipped
+// in any reports.
+func (runner *suiteRunner) skipTest(t *testing.T, method *methodType, reason string) {
+	c := C{T: t, startTime: time.Now()}
+	if runner.report != nil {
+		t.Cleanup(func() {
+			runner.report.addTest(&c, time.Since(c.startTime), runner.tags(method.Info.Name))
+		})
+	}
+	c.skipNow(reason)
+}
+
+// selection records whether a test of the suite is to be run, as decided
+// before any of the suite's fixtures run.
+type selection struct {
+	// skip is why the test is skipped, or "" if it is run.
+	skip string
+	// notes is output for the test from checking it, such as the unmet
+	// requirements ignored with -tc.force-run.
+	notes string
+}
+
+// selectTests decides which of the suite's tests to run, given the shard
+// they must be in if sharded, the tags selected and their requirements. It
+// returns the selection of each test by method name, and whether any test
+// is to be run.
+func (runner *suiteRunner) selectTests(shard shard, sharded bool) (map[string]selection, bool) {
+	selections := make(map[string]selection)
+	selected := false
+	for _, test := range runner.tests {
+		var sel selection
+		if sharded && !shard.contains(test.String()) {
+			sel.skip = fmt.Sprintf("not in shard %v", shard)
+		} else if reason := runner.unselected(test.Info.Name); reason != "" {
+			sel.skip = reason
+		} else {
+			var notes strings.Builder
+			sel.skip = runner.unmetRequirements(&notes, test.Info.Name)
+			sel.notes = notes.String()
+		}
+		selections[test.Info.Name] = sel
+		selected = selected || sel.skip == ""
+	}
+	return selections, selected
+}
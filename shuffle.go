@@ -0,0 +1,78 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+func init() {
+	flag.Var(&shuffleFlag, "tc.shuffle", "Run suites and their tests in a random order: on to pick a seed, or a seed to replay an order")
+}
+
+// shuffleFlag holds the value of the -tc.shuffle flag.
+var shuffleFlag shuffleValue
+
+// shuffleValue is a flag.Value holding the seed tests are shuffled with,
+// if they are shuffled at all.
+type shuffleValue struct {
+	enabled bool
+	seed    int64
+}
+
+func (v *shuffleValue) String() string {
+	if !v.enabled {
+		return "off"
+	}
+	return strconv.FormatInt(v.seed, 10)
+}
+
+func (v *shuffleValue) Set(s string) error {
+	switch s {
+	case "off":
+		*v = shuffleValue{}
+	case "on":
+		*v = shuffleValue{enabled: true, seed: time.Now().UnixNano()}
+	default:
+		seed, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("want on, off or a seed, got %q", s)
+		}
+		*v = shuffleValue{enabled: true, seed: seed}
+	}
+	return nil
+}
+
+// shuffled returns the registered suites in the order RunAll and ListAll
+// take them.
+func shuffled(suites []any) []any {
+	if !shuffleFlag.enabled {
+		return suites
+	}
+	suites = append([]any(nil), suites...)
+	rand.New(rand.NewSource(shuffleFlag.seed)).Shuffle(len(suites), func(i, j int) {
+		suites[i], suites[j] = suites[j], suites[i]
+	})
+	return suites
+}
+
+// shuffleTests permutes the suite's tests if shuffling is enabled. The
+// order depends only on the seed and the suite's name, so that a suite run
+// on its own is shuffled the same way as when it is run with others.
+func (runner *suiteRunner) shuffleTests() {
+	if !shuffleFlag.enabled {
+		return
+	}
+	h := fnv.New64a()
+	h.Write([]byte(suiteName(runner.suite)))
+	r := rand.New(rand.NewSource(shuffleFlag.seed ^ int64(h.Sum64())))
+	r.Shuffle(len(runner.tests), func(i, j int) {
+		runner.tests[i], runner.tests[j] = runner.tests[j], runner.tests[i]
+	})
+}
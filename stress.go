@@ -0,0 +1,177 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+func init() {
+	flag.Var(&stressFlag, "tc.stress", "Run each suite test N times, with its SetUpTest and TearDownTest, stopping at the first failure, as N or N,parallel=P to run P copies of the suite at once")
+}
+
+// stressFlag holds the value of the -tc.stress flag.
+var stressFlag stressValue
+
+// stressValue is a flag.Value holding the number of iterations and the
+// parallelism given by -tc.stress.
+type stressValue struct {
+	iterations int
+	parallel   int
+}
+
+func (v *stressValue) String() string {
+	if v.iterations == 0 {
+		return ""
+	}
+	if v.parallel > 1 {
+		return fmt.Sprintf("%d,parallel=%d", v.iterations, v.parallel)
+	}
+	return strconv.Itoa(v.iterations)
+}
+
+func (v *stressValue) Set(s string) error {
+	if s == "" {
+		*v = stressValue{}
+		return nil
+	}
+	count, options, _ := strings.Cut(s, ",")
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 1 {
+		return fmt.Errorf("invalid number of iterations %q", count)
+	}
+	result := stressValue{iterations: n, parallel: 1}
+	if options != "" {
+		parallel, ok := strings.CutPrefix(options, "parallel=")
+		if !ok {
+			return fmt.Errorf("want N or N,parallel=P, got %q", s)
+		}
+		p, err := strconv.Atoi(parallel)
+		if err != nil || p < 1 {
+			return fmt.Errorf("invalid parallelism %q", parallel)
+		}
+		result.parallel = p
+	}
+	*v = result
+	return nil
+}
+
+// stressFailure is the first iteration of a stressed test that failed,
+// skipped or, with a nil attempt, timed out.
+type stressFailure struct {
+	iteration int
+	elapsed   time.Duration
+	attempt   *attempt
+}
+
+// stress runs the test method the number of times given by -tc.stress,
+// each time against a fresh copy of the suite with its own SetUpTest and
+// TearDownTest, in as many goroutines as the parallelism given. It stops
+// at the first iteration that fails, failing the test with the iteration
+// number, the time taken to get there and the iteration's output. The
+// output of iterations that pass is discarded.
+//
+// Each iteration is run in a synctest bubble and against the timeout as
+// the test would be, and has its own C.Rand numbers. Stressed tests are
+// not retried.
+func (runner *suiteRunner) stress(c *C, method *methodType, args ...reflect.Value) {
+	var (
+		next    atomic.Int64
+		stopped atomic.Bool
+		mu      sync.Mutex
+		failure *stressFailure
+		wg      sync.WaitGroup
+	)
+	start := time.Now()
+	stop := func(iteration int, a *attempt) {
+		stopped.Store(true)
+		mu.Lock()
+		if failure == nil {
+			failure = &stressFailure{iteration, time.Since(start), a}
+		}
+		mu.Unlock()
+	}
+	for range stressFlag.parallel {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			iteration := 0
+			defer func() {
+				// A timed out iteration never returns, having failed
+				// the test and ended this goroutine.
+				if iteration != 0 {
+					stop(iteration, nil)
+				}
+			}()
+			for !stopped.Load() {
+				iteration = int(next.Add(1))
+				if iteration > stressFlag.iterations {
+					iteration = 0
+					return
+				}
+				a := runner.stressIteration(c, iteration, method, args)
+				if a.failed || a.skipped {
+					stop(iteration, a)
+				}
+				iteration = 0
+			}
+		}()
+	}
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	switch {
+	case failure == nil:
+		fmt.Fprintf(c.Output(), "stress: %d iterations with parallel=%d passed in %v\n",
+			stressFlag.iterations, stressFlag.parallel, elapsed)
+	case failure.attempt == nil:
+		c.fail(fmt.Sprintf("stress: iteration %d of %d timed out after %v",
+			failure.iteration, stressFlag.iterations, failure.elapsed))
+	case failure.attempt.failed:
+		c.fail(fmt.Sprintf("stress: iteration %d of %d failed after %v\n%s",
+			failure.iteration, stressFlag.iterations, failure.elapsed,
+			indent(failure.attempt.output.String(), "    ")))
+	default:
+		c.T.Output().Write(failure.attempt.output.Bytes())
+		c.skipNow(fmt.Sprintf("stress: iteration %d of %d skipped", failure.iteration, stressFlag.iterations))
+	}
+}
+
+// stressIteration runs one iteration of a stressed test, returning its
+// outcome. If the iteration times out, the test is failed and the calling
+// goroutine ended, as they are for a test that isn't stressed.
+func (runner *suiteRunner) stressIteration(c *C, iteration int, method *methodType, args []reflect.Value) *attempt {
+	clone := runner.clone()
+	if clone != runner {
+		method = method.bind(reflect.ValueOf(clone.suite))
+	}
+	ic := &C{T: c.T, startTime: time.Now(), runner: clone, iteration: iteration}
+	a := &attempt{}
+	run := func() {
+		ic.runAttempt(a, func() {
+			clone.runWithFixtures(ic, func() {
+				method.Call(ic, args...)
+			})
+		})
+	}
+	if runner.inBubble(method) {
+		bubbled := run
+		run = func() {
+			runInBubble(ic, bubbled)
+		}
+	}
+	if timeout := runner.testTimeout(method); timeout > 0 {
+		runWithTimeout(ic, method, timeout, run)
+	} else {
+		run()
+	}
+	return a
+}
@@ -0,0 +1,34 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"testing"
+	"time"
+)
+
+// Run runs f as a subtest of c called name, and reports whether it
+// succeeded. As with testing.T.Run, f runs in its own goroutine and may
+// call Parallel. It is passed a new *C for the subtest, with its own start
+// time and temporary directories, which a failed assertion stops without
+// stopping c.
+//
+// If the suite has SetUpSubtest or TearDownSubtest methods, they are
+// called with the subtest's *C around f, in the same way as SetUpTest and
+// TearDownTest are around the test. A panic in f fails the subtest.
+func (c *C) Run(name string, f func(c *C)) bool {
+	c.Helper()
+	return c.T.Run(name, func(t *testing.T) {
+		sub := &C{T: t, method: c.method, startTime: time.Now(), runner: c.runner}
+		body := func() {
+			f(sub)
+		}
+		if c.runner == nil {
+			sub.inTestPhase(phaseBody, body)
+			return
+		}
+		runTestBetween(sub, c.runner.setUpSubtest, c.runner.tearDownSubtest,
+			phaseSetUpSubtest, phaseTearDownSubtest, body)
+	})
+}
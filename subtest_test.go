@@ -0,0 +1,88 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc_test
+
+import (
+	"testing"
+
+	"github.com/juju/tc"
+)
+
+type SubtestS struct{}
+
+var _ = tc.InternalSuite(&SubtestS{})
+
+type subtestHelper struct {
+	calls  []string
+	dirs   []string
+	failOn string
+}
+
+func (s *subtestHelper) SetUpTest(c *tc.C) {
+	s.calls = append(s.calls, "SetUpTest")
+}
+
+func (s *subtestHelper) TearDownTest(c *tc.C) {
+	s.calls = append(s.calls, "TearDownTest")
+}
+
+func (s *subtestHelper) SetUpSubtest(c *tc.C) {
+	s.calls = append(s.calls, "SetUpSubtest "+c.TestName())
+}
+
+func (s *subtestHelper) TearDownSubtest(c *tc.C) {
+	s.calls = append(s.calls, "TearDownSubtest "+c.TestName())
+}
+
+func (s *subtestHelper) TestSubtests(c *tc.C) {
+	for _, name := range []string{"one", "two", "three"} {
+		c.Run(name, func(c *tc.C) {
+			s.calls = append(s.calls, c.TestName())
+			s.dirs = append(s.dirs, c.MkDir())
+			switch s.failOn {
+			case name:
+				c.Assert(name, tc.Equals, "")
+			case "panic " + name:
+				panic(name)
+			}
+		})
+	}
+	s.calls = append(s.calls, "after")
+}
+
+func (s *SubtestS) TestSubtests(c *tc.C) {
+	suite := &subtestHelper{}
+	c.T.Run("subtestHelper", func(t *testing.T) {
+		tc.Run(t, suite)
+	})
+	prefix := c.TestName() + "/subtestHelper/TestSubtests/"
+	c.Check(suite.calls, tc.DeepEquals, []string{
+		"SetUpTest",
+		"SetUpSubtest " + prefix + "one", prefix + "one", "TearDownSubtest " + prefix + "one",
+		"SetUpSubtest " + prefix + "two", prefix + "two", "TearDownSubtest " + prefix + "two",
+		"SetUpSubtest " + prefix + "three", prefix + "three", "TearDownSubtest " + prefix + "three",
+		"after",
+		"TearDownTest",
+	})
+	c.Assert(suite.dirs, tc.HasLen, 3)
+	c.Check(suite.dirs[0], tc.Not(tc.Equals), suite.dirs[1])
+}
+
+func (s *SubtestS) TestSubtestFailure(c *tc.C) {
+	exitCode, output := runHelperSuite("subtestHelper", "-helper.fail=two")
+	c.Check(exitCode, tc.Equals, 1)
+	c.Check(output.Status("TestSubtests/one"), tc.Equals, "PASS")
+	c.Check(output.Status("TestSubtests/two"), tc.Equals, "FAIL")
+	c.Check(output.Logs("TestSubtests/two"), tc.Matches, `(?s).*c.Assert\(name, tc.Equals, ""\).*`)
+	c.Check(output.Status("TestSubtests/three"), tc.Equals, "PASS")
+	c.Check(output.Status("TestSubtests"), tc.Equals, "FAIL")
+}
+
+func (s *SubtestS) TestSubtestPanic(c *tc.C) {
+	exitCode, output := runHelperSuite("subtestHelper", "-helper.fail=panic one")
+	c.Check(exitCode, tc.Equals, 1)
+	c.Check(output.Status("TestSubtests/one"), tc.Equals, "FAIL")
+	c.Check(output.Logs("TestSubtests/one"), tc.Matches, `(?s).*\.\.\. Panic: one\n.*`)
+	c.Check(output.Status("TestSubtests/two"), tc.Equals, "PASS")
+}
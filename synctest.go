@@ -0,0 +1,158 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+// SyncTestSuite may be implemented by a suite to run the test methods for
+// which SyncTest returns true in a testing/synctest bubble, along with
+// their SetUpTest and TearDownTest, so that time.Sleep, timers and tickers
+// use the bubble's fake clock. The *C passed to them wraps the bubble's
+// *testing.T, and its time is the bubble's, so times to compare with
+// checkers such as Almost and TimeBetween must be taken inside the bubble
+// too.
+//
+// The test's timeout, leak check and report, and the timings of its
+// phases checked by -tc.budget and printed by -tc.slowest, are still
+// measured against the real clock. Goroutines started by the test must
+// have exited by the time TearDownTest returns, as synctest requires. A
+// test whose goroutines all get blocked in the bubble fails with their
+// stacks, and the suite moves on to its next test.
+type SyncTestSuite interface {
+	SyncTest(method string) bool
+}
+
+// inBubble reports whether the given test method is run in a synctest
+// bubble.
+func (runner *suiteRunner) inBubble(method *methodType) bool {
+	st, ok := runner.suite.(SyncTestSuite)
+	return ok && st.SyncTest(method.Info.Name)
+}
+
+// runInBubble calls run in a synctest bubble, with c wrapping the bubble's
+// *testing.T while it runs. A skip inside the bubble skips the test, as a
+// failure already fails it. If every goroutine in the bubble gets blocked,
+// the test fails with their stacks and they are abandoned, as they are
+// when the test times out.
+func runInBubble(c *C, run func()) {
+	outer := c.T
+	c.clock = startRealClock()
+	defer c.clock.stop()
+	before := make(map[int]bool)
+	for _, g := range goroutines() {
+		before[g.id] = true
+	}
+	defer func() {
+		c.setT(outer)
+		r := recover()
+		if r == nil {
+			return
+		}
+		msg := fmt.Sprint(r)
+		if !strings.HasPrefix(msg, "deadlock: ") {
+			panic(r)
+		}
+		var blocked []goroutine
+		for _, g := range goroutines() {
+			if !before[g.id] && g.inBubble() {
+				blocked = append(blocked, g)
+			}
+		}
+		phase, ok := c.phase.Load().(string)
+		if !ok {
+			phase = "an unknown phase"
+		}
+		c.abandon(outer, fmt.Sprintf("%s in %s\n\n%s", msg, phase, formatGoroutines(blocked)))
+	}()
+	var skipped bool
+	synctest.Test(outer, func(t *testing.T) {
+		c.mu.Lock()
+		// A test abandoned before its bubble starts keeps the test's own
+		// *testing.T, which the runner has already failed.
+		if !c.abandoned.Load() {
+			c.T = t
+		}
+		c.mu.Unlock()
+		c.startTime = time.Now()
+		// Registered first, so that it runs after the fixtures'
+		// cleanups.
+		t.Cleanup(func() {
+			skipped = t.Skipped()
+		})
+		run()
+	})
+	if skipped {
+		outer.SkipNow()
+	}
+}
+
+// setT makes c wrap t again, once a synctest bubble has ended or the test
+// running in it has been abandoned.
+func (c *C) setT(t *testing.T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.T = t
+}
+
+// realClock tells the real time to code in a synctest bubble, whose
+// time.Now tells the bubble's fake time, by asking a goroutine outside
+// the bubble for it.
+type realClock struct {
+	mu   sync.Mutex
+	ask  chan struct{}
+	tell chan time.Time
+	done chan struct{}
+}
+
+// startRealClock starts a realClock, which must be started outside any
+// bubble.
+func startRealClock() *realClock {
+	clock := &realClock{
+		ask:  make(chan struct{}),
+		tell: make(chan time.Time),
+		done: make(chan struct{}),
+	}
+	go func() {
+		for {
+			select {
+			case <-clock.ask:
+				clock.tell <- time.Now()
+			case <-clock.done:
+				return
+			}
+		}
+	}()
+	return clock
+}
+
+// now returns the real time, or the caller's once the clock is stopped.
+func (clock *realClock) now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	select {
+	case clock.ask <- struct{}{}:
+		return <-clock.tell
+	case <-clock.done:
+		return time.Now()
+	}
+}
+
+func (clock *realClock) stop() {
+	close(clock.done)
+}
+
+// now returns the real time, even in a synctest bubble.
+func (c *C) now() time.Time {
+	if c.clock != nil {
+		return c.clock.now()
+	}
+	return time.Now()
+}
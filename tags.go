@@ -0,0 +1,127 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+func init() {
+	flag.Var(&tagsFlag, "tc.tags", "Run only the suite tests with tags matching this expression, such as slow,db+!flaky")
+	flag.Var(&excludeTagsFlag, "tc.exclude-tags", "Skip the suite tests with tags matching this expression")
+}
+
+var (
+	// tagsFlag and excludeTagsFlag hold the -tc.tags and
+	// -tc.exclude-tags expressions.
+	tagsFlag        tagExpr
+	excludeTagsFlag tagExpr
+)
+
+// TaggedSuite may be implemented by a suite to label its test methods, so
+// that they can be selected with the -tc.tags and -tc.exclude-tags flags.
+// Tags maps the names of test methods to their tags, with the tags under
+// the "" key applying to every test in the suite.
+//
+// The flags take an expression of comma separated alternatives, any of
+// which a test's tags must match, where each alternative is a list of tags
+// joined by "+" that must all be present, or absent if prefixed with "!".
+// For example "slow,db+!flaky" matches tests tagged slow, and tests tagged
+// db but not flaky.
+type TaggedSuite interface {
+	Tags() map[string][]string
+}
+
+// tags returns the tags of the named test method, including those of the
+// whole suite.
+func (runner *suiteRunner) tags(method string) []string {
+	ts, ok := runner.suite.(TaggedSuite)
+	if !ok {
+		return nil
+	}
+	all := ts.Tags()
+	var tags []string
+	for _, tag := range append(all[""], all[method]...) {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// unselected returns the reason the named test method is not selected by
+// the tag flags, or "" if it is.
+func (runner *suiteRunner) unselected(method string) string {
+	tags := runner.tags(method)
+	if tagsFlag.set() && !tagsFlag.matches(tags) {
+		return fmt.Sprintf("not selected by -tc.tags=%s", tagsFlag.source)
+	}
+	if excludeTagsFlag.set() && excludeTagsFlag.matches(tags) {
+		return fmt.Sprintf("excluded by -tc.exclude-tags=%s", excludeTagsFlag.source)
+	}
+	return ""
+}
+
+// tagExpr is a flag.Value holding a tag selection expression.
+type tagExpr struct {
+	source       string
+	alternatives [][]tagTerm
+}
+
+// tagTerm is a tag that must be present, or absent if negated.
+type tagTerm struct {
+	tag     string
+	negated bool
+}
+
+func (e *tagExpr) String() string {
+	return e.source
+}
+
+func (e *tagExpr) Set(s string) error {
+	if s == "" {
+		*e = tagExpr{}
+		return nil
+	}
+	var alternatives [][]tagTerm
+	for _, alternative := range strings.Split(s, ",") {
+		var terms []tagTerm
+		for _, term := range strings.Split(alternative, "+") {
+			term = strings.TrimSpace(term)
+			tag, negated := strings.CutPrefix(term, "!")
+			if tag == "" {
+				return fmt.Errorf("empty tag in %q", s)
+			}
+			terms = append(terms, tagTerm{tag: tag, negated: negated})
+		}
+		alternatives = append(alternatives, terms)
+	}
+	*e = tagExpr{source: s, alternatives: alternatives}
+	return nil
+}
+
+func (e *tagExpr) set() bool {
+	return e.source != ""
+}
+
+// matches reports whether a test with the given tags matches the
+// expression.
+func (e *tagExpr) matches(tags []string) bool {
+	for _, terms := range e.alternatives {
+		matched := true
+		for _, term := range terms {
+			if slices.Contains(tags, term.tag) == term.negated {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -17,6 +17,13 @@ type TBC struct {
 
 var _ LikeC = (*TBC)(nil)
 
+// likeC may be embedded alongside a testing.TB to provide the methods of
+// LikeC that testing.TB lacks. The TBC is nested so that the methods of
+// its testing.TB don't clash with those of the one embedded.
+type likeC struct {
+	*TBC
+}
+
 func (tbc *TBC) TestName() string {
 	return tbc.Name()
 }
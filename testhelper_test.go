@@ -40,6 +40,8 @@ import (
 var (
 	helperRunFlag   = flag.String("helper.run", "", "Run helper suite")
 	helperPanicFlag = flag.String("helper.panic", "", "")
+	helperFailFlag  = flag.String("helper.fail", "", "")
+	helperHangFlag  = flag.String("helper.hang", "", "")
 )
 
 func TestHelperSuite(t *testing.T) {
@@ -57,6 +59,56 @@ func TestHelperSuite(t *testing.T) {
 			suite.panicOn = *helperPanicFlag
 		}
 		check.Run(t, suite)
+	case "ChainHelper":
+		check.Run(t, &ChainHelper{chained: true, ChainMiddle: ChainMiddle{
+			ChainBase: ChainBase{failOn: *helperFailFlag},
+		}})
+	case "misspeltSuite":
+		check.Run(t, &misspeltSuite{})
+	case "TimeoutHelper":
+		check.Run(t, &TimeoutHelper{hangOn: *helperHangFlag})
+	case "LeakHelper":
+		check.Run(t, &LeakHelper{})
+	case "ParallelHelper":
+		check.Run(t, &ParallelHelper{})
+	case "ReportHelper":
+		check.Run(t, &ReportHelper{})
+	case "ShuffleHelper":
+		check.Run(t, &ShuffleHelper{})
+	case "TimingHelper":
+		check.Run(t, &TimingHelper{})
+	case "typedHelper":
+		check.RunSuite(t, func(c *check.C) *typedFixture {
+			return &typedFixture{n: 1, calls: &[]string{}}
+		}, nil, typedIncrement)
+	case "TagHelper":
+		check.Run(t, &TagHelper{})
+	case "RetryHelper":
+		check.Run(t, &RetryHelper{})
+	case "SyncTestHelper":
+		check.Run(t, &SyncTestHelper{})
+	case "StressHelper":
+		check.Run(t, &StressHelper{failAt: *helperFailFlag})
+	case "RandHelper":
+		check.Run(t, &RandHelper{})
+	case "IsolationHelper":
+		check.Run(t, &IsolationHelper{})
+	case "IsolationParallelHelper":
+		check.Run(t, &IsolationParallelHelper{})
+	case "RetryEnvHelper":
+		check.Run(t, &RetryEnvHelper{})
+	case "StressRunHelper":
+		check.Run(t, &StressRunHelper{})
+	case "SyncTestHangHelper":
+		check.Run(t, &SyncTestHangHelper{})
+	case "FixtureErrorHelper":
+		check.Run(t, &FixtureErrorHelper{})
+	case "SignatureHelper":
+		check.Run(t, &SignatureHelper{})
+	case "subtestHelper":
+		check.Run(t, &subtestHelper{failOn: *helperFailFlag})
+	case "RequiresHelper":
+		check.Run(t, &RequiresHelper{suiteOS: *helperFailFlag})
 	case "integrationTestHelper":
 		check.Run(t, &integrationTestHelper{})
 	default:
@@ -64,6 +116,18 @@ func TestHelperSuite(t *testing.T) {
 	}
 }
 
+func FuzzHelperSuite(f *testing.F) {
+	if helperRunFlag == nil || *helperRunFlag == "" {
+		f.SkipNow()
+	}
+	switch *helperRunFlag {
+	case "fuzzHelper":
+		check.RunF(f, &fuzzHelper{})
+	default:
+		f.Skip()
+	}
+}
+
 type helperResult []string
 
 var (
@@ -84,6 +148,27 @@ func (result helperResult) Status(test string) string {
 	return ""
 }
 
+// Started returns the names of the tests in the order they started.
+func (result helperResult) Started() []string {
+	var names []string
+	for _, line := range result {
+		match := testRunLine.FindStringSubmatch(line)
+		if match != nil && strings.HasPrefix(match[1], "TestHelperSuite/") {
+			names = append(names, strings.TrimPrefix(match[1], "TestHelperSuite/"))
+		}
+	}
+	return names
+}
+
+func (result helperResult) Paused(test string) bool {
+	for _, line := range result {
+		if line == "=== PAUSE TestHelperSuite/"+test {
+			return true
+		}
+	}
+	return false
+}
+
 func (result helperResult) Logs(test string) string {
 	var lines []string
 	var inTest bool
@@ -105,7 +190,11 @@ func (result helperResult) Logs(test string) string {
 }
 
 func runHelperSuite(name string, args ...string) (code int, output helperResult) {
-	args = append([]string{"-test.v", "-test.run", "TestHelperSuite", "-helper.run", name}, args...)
+	return runHelper("TestHelperSuite", name, args...)
+}
+
+func runHelper(test, name string, args ...string) (code int, output helperResult) {
+	args = append([]string{"-test.v", "-test.run", "^" + test + "$", "-helper.run", name}, args...)
 	cmd := exec.Command(os.Args[0], args...)
 	data, err := cmd.Output()
 	output = strings.Split(string(data), "\n")
@@ -0,0 +1,127 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	slowestFlag       = flag.Int("tc.slowest", 0, "Print the N slowest suite tests and fixtures at the end of the run")
+	budgetFlag        = flag.Duration("tc.budget", 0, "Fail any suite test whose body takes longer than this")
+	fixtureBudgetFlag = flag.Duration("tc.fixture-budget", 0, "Fail any suite test or suite whose fixture methods take longer than this")
+)
+
+// testPhases and suitePhases are the phases timed for each test and for a
+// suite as a whole, in the order they run.
+var (
+	testPhases  = []string{phaseSetUpTest, phaseBody, phaseTearDownTest}
+	suitePhases = []string{phaseSetUpSuite, phaseTearDownSuite}
+)
+
+// checkBudgets fails c if any of the given phases took longer than its
+// budget allows.
+func checkBudgets(c *C, phases []string) {
+	c.Helper()
+	c.mu.Lock()
+	timings := make(map[string]time.Duration, len(c.timings))
+	for phase, d := range c.timings {
+		timings[phase] = d
+	}
+	c.mu.Unlock()
+
+	for _, phase := range phases {
+		budget := *fixtureBudgetFlag
+		if phase == phaseBody {
+			budget = *budgetFlag
+		}
+		if d := timings[phase]; budget > 0 && d > budget {
+			c.fail(fmt.Sprintf("%s took %v in %s, over the budget of %v",
+				c.TestName(), d.Round(time.Millisecond), phase, budget))
+		}
+	}
+}
+
+// timedEntry is a test or suite fixture in the slowest report.
+type timedEntry struct {
+	name     string
+	duration time.Duration
+	phases   map[string]time.Duration
+	order    []string
+}
+
+// printSlowest writes the n slowest tests and suite fixtures of the given
+// suites to w, along with the total time spent in each phase.
+func printSlowest(w io.Writer, n int, suites []*suiteReport) {
+	var entries []timedEntry
+	totals := make(map[string]time.Duration)
+	for _, suite := range suites {
+		for _, phase := range suitePhases {
+			if d, ok := suite.timings[phase]; ok {
+				entries = append(entries, timedEntry{
+					name:     suite.name + "." + phase,
+					duration: d,
+				})
+				totals[phase] += d
+			}
+		}
+		for _, test := range suite.sortedTests() {
+			var total time.Duration
+			for _, phase := range testPhases {
+				total += test.timings[phase]
+				totals[phase] += test.timings[phase]
+			}
+			entries = append(entries, timedEntry{
+				name:     suite.name + "." + test.name,
+				duration: total,
+				phases:   test.timings,
+				order:    testPhases,
+			})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].duration > entries[j].duration
+	})
+
+	fmt.Fprintf(w, "slowest %d of %d:\n", min(n, len(entries)), len(entries))
+	for _, entry := range entries[:min(n, len(entries))] {
+		fmt.Fprintf(w, "%10s  %s", formatSeconds(entry.duration), entry.name)
+		if phases := formatPhases(entry.phases, entry.order); phases != "" {
+			fmt.Fprintf(w, " (%s)", phases)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "time spent in each phase: %s\n",
+		formatPhases(totals, []string{phaseSetUpSuite, phaseSetUpTest, phaseBody, phaseTearDownTest, phaseTearDownSuite}))
+}
+
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3fs", d.Seconds())
+}
+
+// formatPhases returns the given phase durations, in the given order, as
+// a comma separated list.
+func formatPhases(phases map[string]time.Duration, order []string) string {
+	var parts []string
+	for _, phase := range order {
+		if d, ok := phases[phase]; ok {
+			parts = append(parts, fmt.Sprintf("%s %s", phase, formatSeconds(d)))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printSlowestSince prints the slowest report for the suites finished
+// since the given number of suites had been reported.
+func printSlowestSince(start int) {
+	reportMu.Lock()
+	defer reportMu.Unlock()
+	printSlowest(os.Stdout, *slowestFlag, reportedSuites[start:])
+}
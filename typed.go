@@ -0,0 +1,112 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// RunSuite runs each of the given test functions as a test of a suite
+// named after the fixture type F. Every test is passed its own fixture
+// value, made by setup before the test and passed to teardown after it,
+// so that no state is shared between tests unless setup shares it.
+//
+// Tests are named after their functions, which must be named functions or
+// method values rather than function literals, and must have distinct
+// names. Type arguments aren't part of the name, so only one instantiation
+// of a generic function may be given. Tests are otherwise run as the test
+// methods of a suite passed to Run: setup and teardown run where SetUpTest
+// and TearDownTest would, with the same ordering and cleanup rules, and
+// the same flags apply. As with TearDownTest after a failed
+// SetUpTest, teardown is still called if setup fails, with the zero
+// fixture value. Either of setup and teardown may be nil.
+func RunSuite[F any](t *testing.T, setup func(*C) F, teardown func(*C, F), tests ...func(*C, F)) {
+	t.Helper()
+	suite := &typedSuite{fixtureType: reflect.TypeOf((*F)(nil)).Elem()}
+	runner := newSuiteRunner(suite)
+	if setup != nil {
+		runner.setUpTest = chain{suite.method("SetUpTest", setup, func(c *C) {
+			c.fixture = setup(c)
+		})}
+	}
+	if teardown != nil {
+		runner.tearDownTest = chain{suite.method("TearDownTest", teardown, func(c *C) {
+			fixture, _ := c.fixture.(F)
+			teardown(c, fixture)
+		})}
+	}
+	names := make(map[string]bool)
+	for _, test := range tests {
+		name := funcName(test)
+		if names[name] {
+			panic(fmt.Sprintf("tc: RunSuite test functions must have distinct names, but %s is given more than once", name))
+		}
+		names[name] = true
+		runner.tests = append(runner.tests, suite.method(name, test, func(c *C) {
+			fixture, _ := c.fixture.(F)
+			test(c, fixture)
+		}))
+	}
+	runner.shuffleTests()
+	runner.run(t)
+}
+
+// typedSuite is the suite run by RunSuite, which stands in for, and is
+// named after, the fixture type.
+type typedSuite struct {
+	fixtureType reflect.Type
+}
+
+func (s *typedSuite) namedType() reflect.Type {
+	return s.fixtureType
+}
+
+// method returns a suite method with the given name that calls call, and
+// which is declared where fn is.
+func (s *typedSuite) method(name string, fn any, call func(c *C)) *methodType {
+	return &methodType{
+		Value: reflect.ValueOf(call),
+		Info: reflect.Method{
+			Name: name,
+			Type: reflect.FuncOf([]reflect.Type{s.fixtureType, cType}, nil, false),
+			Func: reflect.ValueOf(fn),
+		},
+	}
+}
+
+// funcName returns the name of the test function fn, without its package.
+// It panics if fn is a function literal, as the name the compiler gives
+// those means nothing in -run patterns or reports, and isn't unique.
+func funcName(fn any) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	// Method values are wrapped in functions with a -fm suffix, and the
+	// type arguments of generic functions are left out as [...].
+	name := strings.TrimSuffix(f.Name(), "-fm")
+	name = strings.ReplaceAll(name, "[...]", "")
+	name = name[strings.LastIndex(name, ".")+1:]
+	if isFuncLiteral(name) {
+		file, line := f.FileLine(f.Entry())
+		panic(fmt.Sprintf("tc: RunSuite test functions must be named functions or methods, not the function literal at %s:%d", file, line))
+	}
+	return name
+}
+
+// isFuncLiteral reports whether name is one the compiler gives a function
+// literal, such as func1, or 2 for one nested in another.
+func isFuncLiteral(name string) bool {
+	digits := strings.TrimPrefix(name, "func")
+	if digits == "" {
+		return false
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/juju/tc"
+)
+
+type TypedS struct {
+	calls []string
+}
+
+var _ = tc.InternalSuite(&TypedS{})
+
+type typedFixture struct {
+	n     int
+	calls *[]string
+}
+
+func typedIncrement(c *tc.C, f *typedFixture) {
+	*f.calls = append(*f.calls, c.TestName())
+	// Each test has its own fixture, so sees n as set up.
+	c.Check(f.n, tc.Equals, 1)
+	f.n++
+}
+
+func (s *TypedS) TestRunSuite(c *tc.C) {
+	var calls []string
+	setup := func(c *tc.C) *typedFixture {
+		calls = append(calls, "setup")
+		return &typedFixture{n: 1, calls: &calls}
+	}
+	teardown := func(c *tc.C, f *typedFixture) {
+		calls = append(calls, "teardown")
+		c.Check(f.n, tc.Equals, 2)
+	}
+	c.T.Run("typed", func(t *testing.T) {
+		tc.RunSuite(t, setup, teardown, typedIncrement, s.typedMethod)
+	})
+	prefix := c.TestName() + "/typed/"
+	c.Check(calls, tc.DeepEquals, []string{
+		"setup", prefix + "typedIncrement", "teardown",
+		"setup", prefix + "typedMethod", "teardown",
+	})
+}
+
+func (s *TypedS) typedMethod(c *tc.C, f *typedFixture) {
+	typedIncrement(c, f)
+}
+
+type typedRecorder struct {
+	got []int
+}
+
+func (r *typedRecorder) record(c *tc.C, n int) {
+	r.got = append(r.got, n)
+}
+
+func (s *TypedS) TestRunSuiteWithoutFixtures(c *tc.C) {
+	r := &typedRecorder{}
+	c.T.Run("typed", func(t *testing.T) {
+		tc.RunSuite[int](t, nil, nil, r.record)
+	})
+	c.Check(r.got, tc.DeepEquals, []int{0})
+}
+
+func (s *TypedS) TestRunSuiteFuncLiteral(c *tc.C) {
+	c.Check(func() {
+		tc.RunSuite[int](c.T, nil, nil, func(c *tc.C, n int) {})
+	}, tc.PanicMatches, `tc: RunSuite test functions must be named functions or methods, not the function literal at .*/typed_test.go:\d+`)
+}
+
+// typedGenericNames records the names of the tests typedGeneric runs as.
+var typedGenericNames []string
+
+func typedGeneric[T any](c *tc.C, n int) {
+	typedGenericNames = append(typedGenericNames, c.TestName())
+}
+
+func (s *TypedS) TestRunSuiteGeneric(c *tc.C) {
+	typedGenericNames = nil
+	c.T.Run("typed", func(t *testing.T) {
+		tc.RunSuite[int](t, nil, nil, typedGeneric[string])
+	})
+	c.Check(typedGenericNames, tc.DeepEquals, []string{c.TestName() + "/typed/typedGeneric"})
+
+	c.Check(func() {
+		tc.RunSuite[int](c.T, nil, nil, typedGeneric[string], typedGeneric[bool])
+	}, tc.PanicMatches, `tc: RunSuite test functions must have distinct names, but typedGeneric is given more than once`)
+}
+
+func (s *TypedS) TestRunSuiteStress(c *tc.C) {
+	exitCode, output := runHelperSuite("typedHelper", "-tc.stress=3")
+	c.Check(exitCode, tc.Equals, 0)
+	c.Check(output.Status("typedIncrement"), tc.Equals, "PASS")
+	c.Check(output.Logs("typedIncrement"), tc.Matches, `(?s).*stress: 3 iterations with parallel=1 passed in .*`)
+}
+
+func (s *TypedS) TestRunSuiteName(c *tc.C) {
+	exitCode, output := runHelperSuite("typedHelper", "-tc.slowest=5")
+	c.Check(exitCode, tc.Equals, 0)
+	c.Check(output.Status("typedIncrement"), tc.Equals, "PASS")
+	c.Check(strings.Join(output, "\n"), tc.Matches, `(?s).*\n +\d\.\d+s  typedFixture\.typedIncrement \(.*`)
+}
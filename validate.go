@@ -0,0 +1,328 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+var (
+	strictFlag = flag.Bool("tc.strict", false, "Also check suites for unexported methods that look like tests or fixtures, by parsing their source")
+)
+
+// fixtureNames are the names of the fixture methods the runner calls.
+var fixtureNames = []string{
+	"SetUpSuite", "TearDownSuite", "SetUpTest", "TearDownTest",
+	"SetUpSubtest", "TearDownSubtest",
+}
+
+// entryPoint is the function running a suite, which decides what its
+// fixture methods are passed.
+type entryPoint string
+
+const (
+	entryRun  entryPoint = "Run"
+	entryRunB entryPoint = "RunB"
+	entryRunF entryPoint = "RunF"
+)
+
+// fixtureArg returns the type of the value entry passes to the named
+// fixture method, which the method may take, or take through one of the
+// interfaces it implements.
+func (entry entryPoint) fixtureArg(name string) reflect.Type {
+	switch name {
+	case "SetUpSuite", "TearDownSuite":
+		switch entry {
+		case entryRunB:
+			return bType
+		case entryRunF:
+			return fType
+		}
+	case "SetUpTest", "TearDownTest":
+		if entry == entryRunB {
+			return bType
+		}
+	}
+	return cType
+}
+
+// validateSuite fails c, before anything in the suite has run, if the
+// suite has any of the mistakes found by validate when run by entry. The
+// source of the suite is only parsed for unexported tests and fixtures
+// with -tc.strict.
+func (runner *suiteRunner) validateSuite(c LikeTB, entry entryPoint) {
+	c.Helper()
+	problems := runner.validate(*strictFlag, entry)
+	for _, problem := range problems {
+		c.Error(problem)
+	}
+	if len(problems) > 0 {
+		c.FailNow()
+	}
+}
+
+// validate returns a description of every method of the suite that the
+// runner would either silently ignore, despite it looking like it was meant
+// to be a fixture or test, or that would fail once called by entry because
+// of its signature. If strict is true, unexported methods found by parsing
+// the suite's source are included.
+func (runner *suiteRunner) validate(strict bool, entry entryPoint) []string {
+	var problems []string
+	suiteType := reflect.TypeOf(runner.suite)
+	ptrType := suiteType
+	if suiteType.Kind() != reflect.Ptr {
+		ptrType = reflect.PointerTo(suiteType)
+	}
+
+	for i := 0; i < ptrType.NumMethod(); i++ {
+		method := ptrType.Method(i)
+		where := methodLocation(ptrType, method.Name)
+		name := suiteName(runner.suite) + "." + method.Name
+
+		kind, ok := methodKind(method.Name)
+		if !ok {
+			if fixture := nearFixtureName(method.Name); fixture != "" {
+				problems = append(problems, fmt.Sprintf(
+					"%s: method %s looks like a misspelling of %s and is never called",
+					where, name, fixture))
+			}
+			continue
+		}
+		if _, ok := suiteType.MethodByName(method.Name); !ok {
+			problems = append(problems, fmt.Sprintf(
+				"%s: method %s has a pointer receiver but the suite was passed by value, so it is never called",
+				where, name))
+			continue
+		}
+		first := firstArg(method.Type)
+		if kind == "test" && method.Type.NumIn() == first+2 && validResults(method.Type) && method.Type.In(first) == cType {
+			if _, err := casesProvider(suiteType, method); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", where, err))
+			}
+			continue
+		}
+		if kind == "fixture" && method.Type.NumIn() == first+1 && validResults(method.Type) {
+			in, arg := method.Type.In(first), entry.fixtureArg(method.Name)
+			if !validFixtureArg(in, arg) {
+				problems = append(problems, fmt.Sprintf(
+					"%s: fixture method %s takes %s but %s passes it a %s, so fixtures shared between Run, RunB and RunF must take a tc.LikeC",
+					where, name, in, entry, arg))
+			}
+			continue
+		}
+		if !validSignature(kind, method.Type) {
+			problems = append(problems, fmt.Sprintf(
+				"%s: %s method %s has unsupported signature %s",
+				where, kind, name, signature(method.Type)))
+		}
+	}
+
+	if !strict {
+		return problems
+	}
+	return append(problems, unexportedTestMethods(ptrType)...)
+}
+
+// methodKind returns the kind of suite method, if any, the runner treats a
+// method with the given name as.
+func methodKind(name string) (string, bool) {
+	for _, fixture := range fixtureNames {
+		if name == fixture {
+			return "fixture", true
+		}
+	}
+	for _, kind := range []string{"Test", "Benchmark", "Fuzz"} {
+		if strings.HasPrefix(name, kind) {
+			return strings.ToLower(kind), true
+		}
+	}
+	return "", false
+}
+
+// nearFixtureName returns the name of the fixture method that name appears
+// to be a misspelling of, or "" if it doesn't look like one.
+func nearFixtureName(name string) string {
+	lower := strings.ToLower(name)
+	switch lower {
+	case "setup":
+		return "SetUpTest"
+	case "teardown":
+		return "TearDownTest"
+	}
+	for _, fixture := range fixtureNames {
+		if editDistance(lower, strings.ToLower(fixture)) <= 2 {
+			return fixture
+		}
+	}
+	return ""
+}
+
+// validSignature reports whether a method of the given kind and type,
+// including its receiver, can be called by the runner.
+func validSignature(kind string, methodType reflect.Type) bool {
+	first := firstArg(methodType)
+	if methodType.NumIn() != first+1 || !validResults(methodType) {
+		return false
+	}
+	in := methodType.In(first)
+	switch kind {
+	case "test":
+		return in == cType || in == testingT
+	case "benchmark":
+		return in == bType || in == testingB
+	case "fuzz":
+		return in == fType
+	}
+	return false
+}
+
+// validFixtureArg reports whether a fixture method taking in can be
+// called with arg, one of *C, *B and *F.
+func validFixtureArg(in, arg reflect.Type) bool {
+	switch in {
+	case likeCType, likeTBType, testingTB, arg:
+		return true
+	case testingT:
+		return arg == cType
+	case testingB:
+		return arg == bType
+	}
+	return false
+}
+
+// signature returns the signature of a method type without its receiver.
+func signature(methodType reflect.Type) string {
+	in := make([]reflect.Type, 0, methodType.NumIn())
+	for i := 1; i < methodType.NumIn(); i++ {
+		in = append(in, methodType.In(i))
+	}
+	out := make([]reflect.Type, 0, methodType.NumOut())
+	for i := 0; i < methodType.NumOut(); i++ {
+		out = append(out, methodType.Out(i))
+	}
+	return reflect.FuncOf(in, out, methodType.IsVariadic()).String()
+}
+
+// methodLocation returns the file and line where the named method of t is
+// declared, looking through the wrappers the compiler generates.
+func methodLocation(t reflect.Type, name string) string {
+	types := []reflect.Type{t}
+	if t.Kind() == reflect.Ptr {
+		types = append(types, t.Elem())
+	}
+	for _, t := range types {
+		method, ok := t.MethodByName(name)
+		if !ok {
+			continue
+		}
+		frame, _ := runtime.CallersFrames([]uintptr{method.Func.Pointer()}).Next()
+		if frame.File != "" && frame.File != "<autogenerated>" {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+	}
+	return "<unknown>"
+}
+
+// unexportedTestMethods returns a description of the unexported methods of
+// the suite type that look like they were meant to be tests or fixtures.
+// The runner can't see these through reflection, so they are found by
+// parsing the files declaring the suite's exported methods. Methods called
+// from elsewhere in their file are taken to be helpers and are not reported.
+func unexportedTestMethods(ptrType reflect.Type) []string {
+	files := make(map[string]bool)
+	for i := 0; i < ptrType.NumMethod(); i++ {
+		name := ptrType.Method(i).Name
+		where := methodLocation(ptrType, name)
+		if i := strings.LastIndex(where, ":"); i > 0 && declaresMethod(ptrType, name) {
+			files[where[:i]] = true
+		}
+	}
+	sorted := make([]string, 0, len(files))
+	for file := range files {
+		sorted = append(sorted, file)
+	}
+	sort.Strings(sorted)
+
+	typeName := ptrType.Elem().Name()
+	var problems []string
+	for _, file := range sorted {
+		fset := token.NewFileSet()
+		fnode, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			continue
+		}
+		called := make(map[string]bool)
+		ast.Inspect(fnode, func(node ast.Node) bool {
+			if sel, ok := node.(*ast.SelectorExpr); ok {
+				called[sel.Sel.Name] = true
+			}
+			return true
+		})
+		for _, decl := range fnode.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 || fn.Name.IsExported() {
+				continue
+			}
+			if receiverName(fn.Recv.List[0].Type) != typeName {
+				continue
+			}
+			name := fn.Name.Name
+			if called[name] {
+				continue
+			}
+			var should string
+			if _, ok := methodKind(strings.ToUpper(name[:1]) + name[1:]); ok {
+				should = strings.ToUpper(name[:1]) + name[1:]
+			} else if fixture := nearFixtureName(name); fixture != "" {
+				should = fixture
+			}
+			if should != "" {
+				pos := fset.Position(fn.Pos())
+				problems = append(problems, fmt.Sprintf(
+					"%s:%d: method %s.%s is unexported and is never called, did you mean %s?",
+					pos.Filename, pos.Line, typeName, name, should))
+			}
+		}
+	}
+	return problems
+}
+
+func receiverName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/juju/tc"
+)
+
+type ValidateS struct{}
+
+var _ = tc.InternalSuite(&ValidateS{})
+
+type misspeltSuite struct{}
+
+func (s *misspeltSuite) SetupTest(c *tc.C)     {}
+func (s *misspeltSuite) TeardownSuite(c *tc.C) {}
+func (s *misspeltSuite) Setup(c *tc.C)         {}
+func (s *misspeltSuite) SetUpFakeHome(c *tc.C) {}
+
+func (s *misspeltSuite) testForgotten(c *tc.C) {}
+
+func (s *misspeltSuite) testHelper(c *tc.C, n int) {}
+
+func (s *misspeltSuite) TestUsesHelper(c *tc.C) {
+	s.testHelper(c, 1)
+}
+
+func (s *ValidateS) TestMisspeltFixtures(c *tc.C) {
+	problems := tc.Validate(&misspeltSuite{})
+	c.Assert(problems, tc.HasLen, 4)
+	c.Check(problems[0], tc.Matches, `.*/validate_test.go:\d+: method misspeltSuite.Setup looks like a misspelling of SetUpTest and is never called`)
+	c.Check(problems[1], tc.Matches, `.*/validate_test.go:\d+: method misspeltSuite.SetupTest looks like a misspelling of SetUpTest and is never called`)
+	c.Check(problems[2], tc.Matches, `.*/validate_test.go:\d+: method misspeltSuite.TeardownSuite looks like a misspelling of TearDownSuite and is never called`)
+	c.Check(problems[3], tc.Matches, `.*/validate_test.go:\d+: method misspeltSuite.testForgotten is unexported and is never called, did you mean TestForgotten\?`)
+}
+
+type badSignatureSuite struct{}
+
+func (s *badSignatureSuite) SetUpTest(c *tc.C, n int)                     {}
+func (s *badSignatureSuite) TestArgs(c *tc.C, n int)                      {}
+func (s *badSignatureSuite) TestReturns(c *tc.C) int                      { return 0 }
+func (s *badSignatureSuite) TestB(c *testing.B)                           {}
+func (s *badSignatureSuite) TestGood(c *tc.C)                             {}
+func (s *badSignatureSuite) TestGoodT(t *testing.T)                       {}
+func (s *badSignatureSuite) TestGoodError(c *tc.C) error                  { return nil }
+func (s *badSignatureSuite) TestGoodContext(ctx context.Context, c *tc.C) {}
+
+func (s *ValidateS) TestBadSignatures(c *tc.C) {
+	problems := tc.Validate(&badSignatureSuite{})
+	c.Assert(problems, tc.HasLen, 4)
+	c.Check(problems[0], tc.Matches, `.*/validate_test.go:\d+: fixture method badSignatureSuite.SetUpTest has unsupported signature func\(\*tc.C, int\)`)
+	c.Check(problems[1], tc.Matches, `.*: test method TestArgs takes a case but the suite has no CasesForTestArgs method`)
+	c.Check(problems[2], tc.Matches, `.*: test method badSignatureSuite.TestB has unsupported signature func\(\*testing.B\)`)
+	c.Check(problems[3], tc.Matches, `.*: test method badSignatureSuite.TestReturns has unsupported signature func\(\*tc.C\) int`)
+}
+
+type valueSuite struct{}
+
+func (s valueSuite) TestValue(c *tc.C)    {}
+func (s *valueSuite) TestPointer(c *tc.C) {}
+
+type entrySuite struct{}
+
+func (s *entrySuite) SetUpSuite(c *tc.C)         {}
+func (s *entrySuite) TearDownSuite(c tc.LikeC)   {}
+func (s *entrySuite) SetUpTest(c *testing.T)     {}
+func (s *entrySuite) TearDownTest(c tc.LikeTB)   {}
+func (s *entrySuite) SetUpSubtest(c *tc.C)       {}
+func (s *entrySuite) BenchmarkSomething(b *tc.B) {}
+func (s *entrySuite) FuzzSomething(f *tc.F)      {}
+func (s *entrySuite) TestSomething(c *tc.C)      {}
+
+func (s *ValidateS) TestFixturesForEntryPoint(c *tc.C) {
+	c.Check(tc.ValidateFor("Run", &entrySuite{}), tc.HasLen, 0)
+
+	problems := tc.ValidateFor("RunB", &entrySuite{})
+	c.Assert(problems, tc.HasLen, 2)
+	c.Check(problems[0], tc.Matches, `.*/validate_test.go:\d+: fixture method entrySuite.SetUpSuite takes \*tc.C but RunB passes it a \*tc.B, `+
+		`so fixtures shared between Run, RunB and RunF must take a tc.LikeC`)
+	c.Check(problems[1], tc.Matches, `.*/validate_test.go:\d+: fixture method entrySuite.SetUpTest takes \*testing.T but RunB passes it a \*tc.B, .*`)
+
+	problems = tc.ValidateFor("RunF", &entrySuite{})
+	c.Assert(problems, tc.HasLen, 1)
+	c.Check(problems[0], tc.Matches, `.*/validate_test.go:\d+: fixture method entrySuite.SetUpSuite takes \*tc.C but RunF passes it a \*tc.F, .*`)
+}
+
+func (s *ValidateS) TestPassedByValue(c *tc.C) {
+	problems := tc.Validate(valueSuite{})
+	c.Assert(problems, tc.HasLen, 1)
+	c.Check(problems[0], tc.Matches, `.*/validate_test.go:\d+: method valueSuite.TestPointer has a pointer receiver but the suite was passed by value, so it is never called`)
+
+	c.Check(tc.Validate(&valueSuite{}), tc.HasLen, 0)
+}
+
+func (s *ValidateS) TestValidatedByDefault(c *tc.C) {
+	exitCode, output := runHelperSuite("misspeltSuite")
+	c.Check(exitCode, tc.Equals, 1)
+	c.Check(output.Status("TestUsesHelper"), tc.Equals, "")
+	joined := strings.Join(output, "\n")
+	c.Check(joined, tc.Contains, "misspeltSuite.SetupTest looks like a misspelling of SetUpTest")
+	// Only -tc.strict parses the source for unexported methods.
+	c.Check(joined, tc.Not(tc.Contains), "testForgotten")
+}
+
+func (s *ValidateS) TestStrict(c *tc.C) {
+	exitCode, output := runHelperSuite("misspeltSuite", "-tc.strict")
+	c.Check(exitCode, tc.Equals, 1)
+	c.Check(output.Status("TestUsesHelper"), tc.Equals, "")
+	c.Check(strings.Join(output, "\n"), tc.Contains, "misspeltSuite.SetupTest looks like a misspelling of SetUpTest")
+	c.Check(strings.Join(output, "\n"), tc.Contains, "misspeltSuite.testForgotten is unexported")
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package tc
+
+import (
+	"flag"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+)
+
+var (
+	timeoutFlag = flag.Duration("tc.timeout", 0, "Fail any suite test taking longer than this, fixtures included")
+)
+
+// TimeoutSuite may be implemented by a suite to set the timeout for each of
+// its test methods, including their SetUpTest and TearDownTest. A positive
+// duration overrides the -tc.timeout flag, zero defers to it, and a
+// negative duration means the method has no timeout.
+//
+// A test that times out fails with a dump of the goroutines running code
+// outside of the runtime and testing packages, and the suite moves on to
+// its next test. The goroutine running the test is abandoned, so its
+// TearDownTest is not called, and anything it logs or reports through its
+// C from then on is dropped.
+type TimeoutSuite interface {
+	TimeoutFor(method string) time.Duration
+}
+
+// testTimeout returns the timeout for the given test method, or zero if it
+// has none.
+func (runner *suiteRunner) testTimeout(method *methodType) time.Duration {
+	timeout := *timeoutFlag
+	if ts, ok := runner.suite.(TimeoutSuite); ok {
+		if d := ts.TimeoutFor(method.Info.Name); d != 0 {
+			timeout = d
+		}
+	}
+	return max(timeout, 0)
+}
+
+// runWithTimeout calls run in a new goroutine, failing the test if it
+// doesn't return within the timeout. Failures, skips and panics in run are
+// passed on to the test's own goroutine.
+func runWithTimeout(c *C, method *methodType, timeout time.Duration, run func()) {
+	c.Helper()
+	// Saved before run can swap in the *testing.T of a synctest bubble,
+	// which mustn't be failed from outside of it.
+	t := c.T
+	done := make(chan struct{})
+	var (
+		returned  bool
+		recovered any
+	)
+	go func() {
+		defer close(done)
+		defer func() {
+			if !returned {
+				// A nil recovered value here means run called
+				// runtime.Goexit, through FailNow or SkipNow.
+				recovered = recover()
+			}
+		}()
+		run()
+		returned = true
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		if recovered != nil {
+			panic(recovered)
+		}
+		if !returned {
+			runtime.Goexit()
+		}
+	case <-timer.C:
+		var running []goroutine
+		for _, g := range goroutines() {
+			if !g.isSystem() {
+				running = append(running, g)
+			}
+		}
+		phase, ok := c.phase.Load().(string)
+		if !ok {
+			phase = "an unknown phase"
+		}
+		c.abandon(t, fmt.Sprintf("%s timed out after %v in %s\n\n%s",
+			method, timeout, phase, formatGoroutines(running)))
+	}
+}
+
+// abandon gives up on the goroutine running the test, which from then on
+// can't report anything, and fails the test through its own t, printing
+// message directly as c.fail does.
+func (c *C) abandon(t *testing.T, message string) {
+	c.abandoned.Store(true)
+	c.setT(t)
+	c.recordFailure(message)
+	fmt.Fprintln(t.Output(), message)
+	t.FailNow()
+}